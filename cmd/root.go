@@ -26,8 +26,13 @@ func loadConfig(cmd *cobra.Command) (*config.Config, error) {
 	v := viper.New()
 
 	v.SetDefault("api.port", 8080)
+	v.SetDefault("api.tls.reloadInterval", 60)
+	v.SetDefault("api.basicAuth.reloadInterval", 30)
+	v.SetDefault("api.kubernetesAuth.cacheTTL", 60)
+	v.SetDefault("rest.eventHistory", 100)
 	v.SetDefault("blockReports.source", "Kyverno Event")
 	v.SetDefault("blockReports.results.maxPerReport", 100)
+	v.SetDefault("blockReports.history", 1000)
 
 	v.SetDefault("leaderElection.releaseOnCancel", true)
 	v.SetDefault("leaderElection.leaseDuration", 15)
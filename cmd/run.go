@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"flag"
+	"sync/atomic"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -44,18 +45,53 @@ func newRunCMD() *cobra.Command {
 				return err
 			}
 
-			server := resolver.APIServer(cmd.Context(), policyClient.HasSynced)
+			server, err := resolver.APIServer(cmd.Context(), policyClient.HasSynced)
+			if err != nil {
+				return err
+			}
+
+			if c.API.BasicAuth.SecretRef != "" {
+				watcher, err := resolver.BasicAuthWatcher(cmd.Context())
+				if err != nil {
+					return err
+				}
+
+				stop := make(chan struct{})
+				defer close(stop)
+
+				if err = watcher.Run(stop); err != nil {
+					return err
+				}
+			}
 
 			if c.REST.Enabled || c.BlockReports.Enabled {
 				resolver.RegisterStoreListener()
 			}
 
+			if c.REST.Enabled || c.Metrics.Enabled {
+				namespaces, err := resolver.NamespaceClient()
+				if err != nil {
+					return err
+				}
+
+				stop := make(chan struct{})
+				defer close(stop)
+
+				if err = namespaces.Run(stop); err != nil {
+					return err
+				}
+			}
+
 			if c.REST.Enabled {
+				resolver.RegisterStreamListener()
 				server.RegisterREST()
 			}
 
 			if c.Metrics.Enabled {
-				resolver.RegisterMetricsListener()
+				if err = resolver.RegisterMetricsListener(); err != nil {
+					return err
+				}
+
 				server.RegisterMetrics()
 			}
 
@@ -71,42 +107,50 @@ func newRunCMD() *cobra.Command {
 					return err
 				}
 
+				// the events are watched on every replica to serve the violation APIs,
+				// PolicyReports are only written by the current leader
+				leader := &atomic.Bool{}
+				leader.Store(!c.LeaderElection.Enabled)
+
 				resolver.ViolationPublisher().RegisterListener(func(pv violation.PolicyViolation) {
+					if !leader.Load() {
+						return
+					}
+
 					policyReportClient.ProcessViolation(ctx, pv)
 				})
 
-				var stop chan struct{}
-				defer close(stop)
-
-				leClient, err := resolver.LeaderElectionClient()
-				if err != nil {
-					return err
+				if c.REST.Enabled {
+					resolver.RegisterViolationStreamListener()
+					resolver.RegisterViolationStoreListener()
 				}
 
+				stop := make(chan struct{})
+				defer close(stop)
+
 				if c.LeaderElection.Enabled {
+					leClient, err := resolver.LeaderElectionClient()
+					if err != nil {
+						return err
+					}
+
 					leClient.RegisterOnStart(func(c context.Context) {
 						logger.Info("started leadership")
-
-						stop = make(chan struct{})
-
-						if err = eventClient.Run(stop); err != nil {
-							logger.Error("failed to run EventClient", zap.Error(err))
-						}
+						leader.Store(true)
 					}).RegisterOnNew(func(currentID, lockID string) {
 						if currentID != lockID {
 							logger.Info("leadership", zap.String("leader", currentID))
 						}
 					}).RegisterOnStop(func() {
 						logger.Info("stopped leadership")
-						close(stop)
+						leader.Store(false)
 					})
 
 					go leClient.Run(cmd.Context())
-				} else {
-					stop = make(chan struct{})
-					if err = eventClient.Run(stop); err != nil {
-						return err
-					}
+				}
+
+				if err = eventClient.Run(stop); err != nil {
+					return err
 				}
 			}
 
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+)
+
+// defaultWebhookTimeoutSeconds is used by Kyverno for policies without webhookTimeoutSeconds
+const defaultWebhookTimeoutSeconds = 10
+
+const (
+	RiskHigh   = "high"
+	RiskMedium = "medium"
+	RiskLow    = "low"
+)
+
+// AdmissionRisk is a risky combination of admission settings
+type AdmissionRisk struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// PolicyAdmissionRisks are the admission settings and risks of a policy
+type PolicyAdmissionRisks struct {
+	Policy                  *Policy          `json:"policy"`
+	Kind                    string           `json:"kind"`
+	FailurePolicy           string           `json:"failurePolicy"`
+	ValidationFailureAction string           `json:"validationFailureAction"`
+	ApplyRules              string           `json:"applyRules"`
+	WebhookTimeoutSeconds   *int32           `json:"webhookTimeoutSeconds,omitempty"`
+	Risks                   []*AdmissionRisk `json:"risks"`
+}
+
+// AdmissionRiskSummary counts the risks per severity
+type AdmissionRiskSummary struct {
+	High   int `json:"high"`
+	Medium int `json:"medium"`
+	Low    int `json:"low"`
+}
+
+// AdmissionRiskReport lists all policies with at least one risk
+type AdmissionRiskReport struct {
+	Summary  AdmissionRiskSummary    `json:"summary"`
+	Policies []*PolicyAdmissionRisks `json:"policies"`
+}
+
+// AdmissionRisks checks the failure policy, webhook timeout and admission settings of all policies
+func AdmissionRisks(policies []kyverno.Policy) *AdmissionRiskReport {
+	report := &AdmissionRiskReport{Policies: make([]*PolicyAdmissionRisks, 0)}
+
+	for _, policy := range policies {
+		risks := policyRisks(policy)
+		if len(risks) == 0 {
+			continue
+		}
+
+		for _, risk := range risks {
+			switch risk.Severity {
+			case RiskHigh:
+				report.Summary.High++
+			case RiskMedium:
+				report.Summary.Medium++
+			case RiskLow:
+				report.Summary.Low++
+			}
+		}
+
+		report.Policies = append(report.Policies, &PolicyAdmissionRisks{
+			Policy:                  &Policy{Name: policy.Name, Namespace: policy.Namespace, UID: policy.UID},
+			Kind:                    policy.Kind,
+			FailurePolicy:           policy.FailurePolicy,
+			ValidationFailureAction: kyverno.NormalizeAction(policy.ValidationFailureAction),
+			ApplyRules:              policy.ApplyRules,
+			WebhookTimeoutSeconds:   policy.WebhookTimeoutSeconds,
+			Risks:                   risks,
+		})
+	}
+
+	sort.SliceStable(report.Policies, func(i, j int) bool {
+		a, b := report.Policies[i], report.Policies[j]
+		if a.Policy.Namespace != b.Policy.Namespace {
+			return a.Policy.Namespace < b.Policy.Namespace
+		}
+
+		return a.Policy.Name < b.Policy.Name
+	})
+
+	return report
+}
+
+func policyRisks(policy kyverno.Policy) []*AdmissionRisk {
+	risks := make([]*AdmissionRisk, 0)
+
+	counts := make(map[string]int)
+	for _, rule := range policy.Rules {
+		counts[kindRuleType(rule)]++
+	}
+
+	enforcedRules := 0
+	if enforces(policy) {
+		enforcedRules = counts[RuleTypeValidate] + counts[RuleTypeVerifyImages]
+	}
+
+	if policy.FailurePolicy == "Ignore" && enforcedRules > 0 {
+		risks = append(risks, &AdmissionRisk{
+			Check:    "ignore-enforce",
+			Severity: RiskHigh,
+			Message:  "failurePolicy Ignore admits resources without validation if the webhook fails or times out",
+		})
+	}
+
+	if policy.ApplyRules == "One" && enforcedRules > 1 {
+		risks = append(risks, &AdmissionRisk{
+			Check:    "apply-one-enforce",
+			Severity: RiskMedium,
+			Message:  "applyRules One stops after the first matching rule, further enforced rules are skipped",
+		})
+	}
+
+	if policy.SchemaValidation != nil && !*policy.SchemaValidation {
+		risks = append(risks, &AdmissionRisk{
+			Check:    "schema-validation-disabled",
+			Severity: RiskMedium,
+			Message:  "schemaValidation false skips the schema checks of the policy and of patched resources",
+		})
+	}
+
+	if policy.FailurePolicy == "Fail" && policy.WebhookTimeoutSeconds != nil && *policy.WebhookTimeoutSeconds > defaultWebhookTimeoutSeconds {
+		risks = append(risks, &AdmissionRisk{
+			Check:    "fail-long-timeout",
+			Severity: RiskMedium,
+			Message:  fmt.Sprintf("failurePolicy Fail blocks admission requests for up to %d seconds if the webhook is slow", *policy.WebhookTimeoutSeconds),
+		})
+	}
+
+	if policy.MutateExistingOnPolicyUpdate && counts[RuleTypeMutate] > 0 {
+		risks = append(risks, &AdmissionRisk{
+			Check:    "mutate-existing-on-update",
+			Severity: RiskLow,
+			Message:  "existing resources are mutated on every policy update",
+		})
+	}
+
+	if policy.GenerateExistingOnPolicyUpdate && counts[RuleTypeGenerate] > 0 {
+		risks = append(risks, &AdmissionRisk{
+			Check:    "generate-existing-on-update",
+			Severity: RiskLow,
+			Message:  "resources are generated for existing triggers on every policy update",
+		})
+	}
+
+	return risks
+}
+
+// enforces reports whether the policy enforces validation in at least one namespace
+func enforces(policy kyverno.Policy) bool {
+	if kyverno.NormalizeAction(policy.ValidationFailureAction) == kyverno.ActionEnforce {
+		return true
+	}
+
+	if policy.Kind != kyverno.ClusterPolicyKind {
+		return false
+	}
+
+	for _, override := range policy.ValidationFailureActionOverrides {
+		if kyverno.NormalizeAction(override.Action) == kyverno.ActionEnforce {
+			return true
+		}
+	}
+
+	return false
+}
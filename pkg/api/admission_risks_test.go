@@ -0,0 +1,126 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+)
+
+func newRiskPolicyStore() *kyverno.PolicyStore {
+	timeout := int32(30)
+	schemaValidation := false
+
+	store := kyverno.NewPolicyStore()
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "require-labels", UID: "1", FailurePolicy: "Ignore", ApplyRules: "One", ValidationFailureAction: "Audit",
+		ValidationFailureActionOverrides: []*kyverno.ValidationFailureActionOverride{{Action: "enforce", Namespaces: []string{"prod"}}},
+		Rules:                            []*kyverno.Rule{{Name: "check-team", Type: "validation"}, {Name: "check-app", Type: "validation"}},
+	})
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "add-labels", UID: "2", FailurePolicy: "Fail", ApplyRules: "All", WebhookTimeoutSeconds: &timeout, SchemaValidation: &schemaValidation, MutateExistingOnPolicyUpdate: true,
+		Rules: []*kyverno.Rule{{Name: "add-team", Type: "mutation"}},
+	})
+	store.Add(kyverno.Policy{Kind: "Policy", Name: "audit-only", Namespace: "team", UID: "3", FailurePolicy: "Ignore", ApplyRules: "All", ValidationFailureAction: "Audit",
+		Rules: []*kyverno.Rule{{Name: "check-team", Type: "validation"}},
+	})
+	store.Add(kyverno.Policy{Kind: "Policy", Name: "enforce", Namespace: "team", UID: "4", FailurePolicy: "Ignore", ApplyRules: "All", ValidationFailureAction: "Enforce",
+		Rules: []*kyverno.Rule{{Name: "signed", Type: "validation", VerifyImages: []*kyverno.VerifyImage{{ImageReferences: []string{"*"}}}}},
+	})
+
+	return store
+}
+
+func riskChecks(policy *api.PolicyAdmissionRisks) []string {
+	checks := make([]string, 0, len(policy.Risks))
+	for _, risk := range policy.Risks {
+		checks = append(checks, risk.Check)
+	}
+
+	return checks
+}
+
+func Test_AdmissionRisks(t *testing.T) {
+	report := api.AdmissionRisks(newRiskPolicyStore().List())
+
+	if len(report.Policies) != 3 {
+		t.Fatalf("Expected 3 policies with risks, got %d", len(report.Policies))
+	}
+
+	if report.Summary.High != 2 || report.Summary.Medium != 3 || report.Summary.Low != 1 {
+		t.Errorf("Unexpected summary: %+v", report.Summary)
+	}
+
+	if p := report.Policies[0]; p.Policy.Name != "add-labels" || strings.Join(riskChecks(p), ",") != "schema-validation-disabled,fail-long-timeout,mutate-existing-on-update" {
+		t.Errorf("Unexpected risks of add-labels: %v", riskChecks(p))
+	}
+
+	if p := report.Policies[1]; p.Policy.Name != "require-labels" || strings.Join(riskChecks(p), ",") != "ignore-enforce,apply-one-enforce" {
+		t.Errorf("Expected override to enforce require-labels, got %v", riskChecks(p))
+	}
+
+	if p := report.Policies[2]; p.Policy.Name != "enforce" || p.ValidationFailureAction != "Enforce" || strings.Join(riskChecks(p), ",") != "ignore-enforce" {
+		t.Errorf("Unexpected risks of team/enforce: %v", riskChecks(p))
+	}
+}
+
+func Test_AdmissionRiskAPI(t *testing.T) {
+	handler := api.AdmissionRiskHandler(newRiskPolicyStore(), reportTemplates.AdmissionRisk)
+
+	t.Run("json", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/admission-risk-reporting?format=json", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+
+		report := api.AdmissionRiskReport{}
+		if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(report.Policies) != 3 || report.Summary.High != 2 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/admission-risk-reporting", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+		if !strings.Contains(rr.Body.String(), "ClusterPolicy: require-labels") || !strings.Contains(rr.Body.String(), "ignore-enforce") {
+			t.Error("Expected rendered admission risk report")
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/admission-risk-reporting?format=pdf", nil))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("namespace authorization", func(t *testing.T) {
+		secured := withUser("dev", api.NamespaceAuthorization(api.NewStaticNamespaceAuthorizer(map[string][]string{"dev": {"other"}}), handler))
+
+		rr := httptest.NewRecorder()
+		secured(rr, httptest.NewRequest("GET", "/admission-risk-reporting?format=json", nil))
+
+		report := api.AdmissionRiskReport{}
+		if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(report.Policies) != 2 {
+			t.Errorf("Expected no Policies of the team namespace, got %+v", report.Policies)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/wildcard"
+)
+
+// namespaceNameLabel is set by Kubernetes on every namespace
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// NamespaceLookup provides the labels of an existing namespace
+type NamespaceLookup interface {
+	NamespaceLabels(name string) (map[string]string, bool)
+}
+
+// ApplicableRule is a rule applying to resources of a namespace. Action is the effective
+// validation failure action of validate and verifyImages rules. PartiallyExcluded is set
+// if an exclude block excludes some of the resources in the namespace.
+type ApplicableRule struct {
+	Policy            *Policy `json:"policy"`
+	Kind              string  `json:"kind"`
+	Rule              string  `json:"rule"`
+	Type              string  `json:"type"`
+	Action            string  `json:"action,omitempty"`
+	PartiallyExcluded bool    `json:"partiallyExcluded"`
+}
+
+// NamespaceApplicability of all rules for a namespace
+type NamespaceApplicability struct {
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Rules     []*ApplicableRule `json:"rules"`
+}
+
+// ApplicableRules evaluates the namespaces and namespaceSelectors of the match and exclude blocks of all rules
+// for the namespace. Conditions on other resource attributes and preconditions are not evaluated.
+func ApplicableRules(policies []kyverno.Policy, namespace string, namespaceLabels map[string]string) *NamespaceApplicability {
+	result := &NamespaceApplicability{Namespace: namespace, Labels: namespaceLabels, Rules: make([]*ApplicableRule, 0)}
+
+	for _, policy := range policies {
+		if policy.Namespace != "" && policy.Namespace != namespace {
+			continue
+		}
+
+		for _, rule := range policy.Rules {
+			if !matchesNamespace(rule.Match, namespace, namespaceLabels) {
+				continue
+			}
+
+			excluded, partially := excludesNamespace(rule.Exclude, namespace, namespaceLabels)
+			if excluded {
+				continue
+			}
+
+			item := &ApplicableRule{
+				Policy:            &Policy{Name: policy.Name, Namespace: policy.Namespace, UID: policy.UID},
+				Kind:              policy.Kind,
+				Rule:              rule.Name,
+				Type:              kindRuleType(rule),
+				PartiallyExcluded: partially,
+			}
+
+			if item.Type == RuleTypeValidate || item.Type == RuleTypeVerifyImages {
+				item.Action = policy.EffectiveAction(namespace, namespaceLabels)
+			}
+
+			result.Rules = append(result.Rules, item)
+		}
+	}
+
+	sort.SliceStable(result.Rules, func(i, j int) bool {
+		a, b := result.Rules[i], result.Rules[j]
+		if a.Policy.Namespace != b.Policy.Namespace {
+			return a.Policy.Namespace < b.Policy.Namespace
+		}
+		if a.Policy.Name != b.Policy.Name {
+			return a.Policy.Name < b.Policy.Name
+		}
+
+		return a.Rule < b.Rule
+	})
+
+	return result
+}
+
+// filterSelectsNamespace reports whether the namespaces and the namespaceSelector of the filter select the namespace
+func filterSelectsNamespace(filter *kyverno.ResourceFilter, namespace string, namespaceLabels map[string]string) bool {
+	resources := filter.Resources
+	if resources == nil {
+		return true
+	}
+
+	if len(resources.Namespaces) > 0 {
+		if _, ok := wildcard.MatchAny(resources.Namespaces, namespace); !ok {
+			return false
+		}
+	}
+
+	if resources.NamespaceSelector != nil && !kyverno.SelectorMatches(resources.NamespaceSelector, namespaceLabels) {
+		return false
+	}
+
+	return true
+}
+
+// matchesNamespace reports whether the match block can select resources of the namespace
+func matchesNamespace(match *kyverno.MatchResources, namespace string, namespaceLabels map[string]string) bool {
+	if match == nil {
+		return true
+	}
+
+	for _, filter := range match.All {
+		if !filterSelectsNamespace(filter, namespace, namespaceLabels) {
+			return false
+		}
+	}
+
+	if len(match.Any) == 0 {
+		return true
+	}
+
+	for _, filter := range match.Any {
+		if filterSelectsNamespace(filter, namespace, namespaceLabels) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// excludesNamespace reports whether the exclude block excludes all resources of the namespace or only a part of them.
+// Only filters without further resource or user conditions exclude the whole namespace.
+func excludesNamespace(exclude *kyverno.MatchResources, namespace string, namespaceLabels map[string]string) (bool, bool) {
+	if exclude == nil {
+		return false, false
+	}
+
+	selects := func(filter *kyverno.ResourceFilter) (bool, bool) {
+		if filter.Resources == nil || (len(filter.Resources.Namespaces) == 0 && filter.Resources.NamespaceSelector == nil) {
+			// filters without namespace conditions can exclude resources in any namespace
+			return false, true
+		}
+
+		if !filterSelectsNamespace(filter, namespace, namespaceLabels) {
+			return false, false
+		}
+
+		r := filter.Resources
+		whole := len(r.Kinds) == 0 && len(r.Names) == 0 && len(r.Annotations) == 0 && r.Selector == nil &&
+			len(filter.Roles) == 0 && len(filter.ClusterRoles) == 0 && len(filter.Subjects) == 0
+
+		return whole, !whole
+	}
+
+	partially := false
+
+	for _, filter := range exclude.Any {
+		whole, partial := selects(filter)
+		if whole {
+			return true, false
+		}
+
+		partially = partially || partial
+	}
+
+	if len(exclude.All) > 0 {
+		allWhole, selected := true, true
+		for _, filter := range exclude.All {
+			whole, partial := selects(filter)
+			allWhole = allWhole && whole
+			selected = selected && (whole || partial)
+		}
+
+		if selected && allWhole {
+			return true, false
+		}
+
+		partially = partially || selected
+	}
+
+	return false, partially
+}
+
+// namespaceLabelsFor adds the name label Kubernetes sets on every namespace
+func namespaceLabelsFor(namespace string, namespaceLabels map[string]string) map[string]string {
+	set := make(map[string]string, len(namespaceLabels)+1)
+	for key, value := range namespaceLabels {
+		set[key] = value
+	}
+
+	if _, ok := set[namespaceNameLabel]; !ok && namespace != "" {
+		set[namespaceNameLabel] = namespace
+	}
+
+	return set
+}
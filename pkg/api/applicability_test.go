@@ -0,0 +1,189 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+)
+
+func newApplicabilityPolicyStore() *kyverno.PolicyStore {
+	prod := &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+
+	store := kyverno.NewPolicyStore()
+	store.Add(kyverno.Policy{
+		Kind:                    "ClusterPolicy",
+		Name:                    "require-labels",
+		ValidationFailureAction: "Audit",
+		ValidationFailureActionOverrides: []*kyverno.ValidationFailureActionOverride{
+			{Action: "Enforce", NamespaceSelector: prod},
+		},
+		Rules: []*kyverno.Rule{
+			{Name: "check-team", Type: "validation", Match: resources("Pod"), Exclude: &kyverno.MatchResources{Any: []*kyverno.ResourceFilter{
+				{Resources: &kyverno.ResourceDescription{Namespaces: []string{"kube-*"}}},
+				{Resources: &kyverno.ResourceDescription{Kinds: []string{"Pod"}, Namespaces: []string{"shop"}, Names: []string{"debug"}}},
+			}}},
+			{Name: "add-defaults", Type: "mutation", Match: &kyverno.MatchResources{Any: []*kyverno.ResourceFilter{
+				{Resources: &kyverno.ResourceDescription{Kinds: []string{"Pod"}, NamespaceSelector: prod}},
+			}}},
+		},
+	})
+	store.Add(kyverno.Policy{Kind: "Policy", Name: "team-rules", Namespace: "shop", ValidationFailureAction: "enforce", Rules: []*kyverno.Rule{
+		{Name: "check-image", Type: "validation", Match: resources("Pod")},
+	}})
+
+	return store
+}
+
+type namespaceLookup map[string]map[string]string
+
+func (n namespaceLookup) NamespaceLabels(name string) (map[string]string, bool) {
+	labels, ok := n[name]
+	return labels, ok
+}
+
+func Test_ApplicableRules(t *testing.T) {
+	policies := newApplicabilityPolicyStore().List()
+
+	t.Run("excluded namespace", func(t *testing.T) {
+		if result := api.ApplicableRules(policies, "kube-system", nil); len(result.Rules) != 0 {
+			t.Errorf("Expected no rules, got %+v", result.Rules)
+		}
+	})
+
+	t.Run("namespace with labels", func(t *testing.T) {
+		result := api.ApplicableRules(policies, "shop", map[string]string{"env": "prod"})
+		if len(result.Rules) != 3 {
+			t.Fatalf("Expected 3 rules, got %d", len(result.Rules))
+		}
+
+		mutate, validate, namespaced := result.Rules[0], result.Rules[1], result.Rules[2]
+		if mutate.Rule != "add-defaults" || mutate.Action != "" {
+			t.Errorf("Expected mutation rule without action, got %+v", mutate)
+		}
+		if validate.Rule != "check-team" || validate.Action != kyverno.ActionEnforce || !validate.PartiallyExcluded {
+			t.Errorf("Expected partially excluded enforced rule, got %+v", validate)
+		}
+		if namespaced.Rule != "check-image" || namespaced.Action != kyverno.ActionEnforce || namespaced.Kind != "Policy" {
+			t.Errorf("Expected enforced namespaced rule, got %+v", namespaced)
+		}
+	})
+
+	t.Run("namespace without labels", func(t *testing.T) {
+		result := api.ApplicableRules(policies, "dev", nil)
+		if len(result.Rules) != 1 || result.Rules[0].Action != kyverno.ActionAudit || result.Rules[0].PartiallyExcluded {
+			t.Errorf("Expected only the audited validation rule, got %+v", result.Rules)
+		}
+	})
+}
+
+func Test_NamespaceRulesAPI(t *testing.T) {
+	handler := api.NamespaceRulesHandler(newApplicabilityPolicyStore(), nil)
+
+	t.Run("missing namespace", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/namespace-rules", nil))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("namespace from labels", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/namespace-rules?labels=env%3Dprod,kubernetes.io/metadata.name%3Dshop", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+
+		result := api.NamespaceApplicability{}
+		if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Namespace != "shop" || len(result.Rules) != 3 {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("name label selector", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/namespace-rules?namespace=prod", nil))
+
+		result := api.NamespaceApplicability{}
+		if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Labels["kubernetes.io/metadata.name"] != "prod" {
+			t.Errorf("Expected the name label, got %+v", result.Labels)
+		}
+	})
+
+	t.Run("namespace authorization", func(t *testing.T) {
+		secured := withUser("dev", api.NamespaceAuthorization(api.NewStaticNamespaceAuthorizer(map[string][]string{"dev": {"dev"}}), handler))
+
+		rr := httptest.NewRecorder()
+		secured(rr, httptest.NewRequest("GET", "/namespace-rules?namespace=shop", nil))
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusForbidden)
+		}
+	})
+}
+
+func Test_NamespaceRulesAPIWithNamespaceLookup(t *testing.T) {
+	handler := api.NamespaceRulesHandler(newApplicabilityPolicyStore(), namespaceLookup{
+		"shop": {"env": "prod", "kubernetes.io/metadata.name": "shop"},
+	})
+
+	t.Run("labels from lookup", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/namespace-rules?namespace=shop", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+
+		result := api.NamespaceApplicability{}
+		if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(result.Rules) != 3 || result.Labels["env"] != "prod" {
+			t.Fatalf("Expected the rules of the selector-based override, got %+v", result)
+		}
+		if validate := result.Rules[1]; validate.Rule != "check-team" || validate.Action != kyverno.ActionEnforce {
+			t.Errorf("Expected the enforce override of the namespace selector, got %+v", validate)
+		}
+	})
+
+	t.Run("explicit labels", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/namespace-rules?namespace=shop&labels=env%3Ddev", nil))
+
+		result := api.NamespaceApplicability{}
+		if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Labels["env"] != "dev" {
+			t.Errorf("Expected the labels of the query, got %+v", result.Labels)
+		}
+	})
+
+	t.Run("unknown namespace", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/namespace-rules?namespace=unknown", nil))
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusNotFound)
+		}
+	})
+}
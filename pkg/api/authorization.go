@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sync"
+
+	"go.uber.org/zap"
+	authzv1 "k8s.io/api/authorization/v1"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting"
+)
+
+type namespaceAccessKey struct{}
+
+// NamespaceAccess checks if the current request is allowed to access the given namespace.
+// An empty namespace stands for cluster scoped data.
+type NamespaceAccess = func(namespace string) bool
+
+// NamespaceAuthorizer decides if a User is allowed to access the data of a namespace
+type NamespaceAuthorizer interface {
+	Allowed(ctx context.Context, user *User, namespace string) (bool, error)
+}
+
+// SubjectAccessNamespaceAuthorizer allows the access to namespaces the User can get
+type SubjectAccessNamespaceAuthorizer struct {
+	auth *KubernetesAuth
+}
+
+func (a *SubjectAccessNamespaceAuthorizer) Allowed(ctx context.Context, user *User, namespace string) (bool, error) {
+	attributes := authzv1.ResourceAttributes{Verb: "get", Resource: "namespaces", Name: namespace}
+	if namespace == "" {
+		attributes.Verb = "list"
+	}
+
+	return a.auth.review(ctx, user, authzv1.SubjectAccessReviewSpec{ResourceAttributes: &attributes})
+}
+
+// NewSubjectAccessNamespaceAuthorizer constructor for SubjectAccessNamespaceAuthorizer
+func NewSubjectAccessNamespaceAuthorizer(auth *KubernetesAuth) *SubjectAccessNamespaceAuthorizer {
+	return &SubjectAccessNamespaceAuthorizer{auth: auth}
+}
+
+// StaticNamespaceAuthorizer allows the access to a configured list of namespace patterns per user.
+// Patterns support shell wildcards, cluster scoped data requires the "*" pattern.
+type StaticNamespaceAuthorizer struct {
+	namespaces map[string][]string
+}
+
+func (a *StaticNamespaceAuthorizer) Allowed(_ context.Context, user *User, namespace string) (bool, error) {
+	for _, pattern := range a.namespaces[user.Name] {
+		if pattern == "*" {
+			return true, nil
+		}
+
+		if namespace == "" {
+			continue
+		}
+
+		if ok, _ := path.Match(pattern, namespace); ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// NewStaticNamespaceAuthorizer constructor for StaticNamespaceAuthorizer
+func NewStaticNamespaceAuthorizer(namespaces map[string][]string) *StaticNamespaceAuthorizer {
+	return &StaticNamespaceAuthorizer{namespaces: namespaces}
+}
+
+// NamespaceAuthorization adds the NamespaceAccess of the authenticated User to the request context.
+// Requests without authenticated User have no access to namespaced data.
+func NamespaceAuthorization(authorizer NamespaceAuthorizer, next http.HandlerFunc) http.HandlerFunc {
+	if authorizer == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		user, ok := UserFrom(ctx)
+
+		results := map[string]bool{}
+		lock := &sync.Mutex{}
+
+		access := func(namespace string) bool {
+			if !ok {
+				return false
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			if allowed, found := results[namespace]; found {
+				return allowed
+			}
+
+			allowed, err := authorizer.Allowed(ctx, user, namespace)
+			if err != nil {
+				zap.L().Error("failed to authorize namespace access", zap.String("user", user.Name), zap.String("namespace", namespace), zap.Error(err))
+			}
+
+			results[namespace] = allowed
+
+			return allowed
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, namespaceAccessKey{}, access)))
+	}
+}
+
+// namespaceAllowed checks the NamespaceAccess of the request, all namespaces are allowed without authorization
+func namespaceAllowed(req *http.Request, namespace string) bool {
+	access, ok := req.Context().Value(namespaceAccessKey{}).(NamespaceAccess)
+	if !ok {
+		return true
+	}
+
+	return access(namespace)
+}
+
+// authorizedPolicies removes all namespaced Policies of not accessible namespaces, ClusterPolicies are always visible
+func authorizedPolicies(req *http.Request, policies []kyverno.Policy) []kyverno.Policy {
+	list := make([]kyverno.Policy, 0, len(policies))
+	for _, policy := range policies {
+		if policy.Namespace == "" || namespaceAllowed(req, policy.Namespace) {
+			list = append(list, policy)
+		}
+	}
+
+	return list
+}
+
+// authorizedPolicyData removes all Groups of not accessible namespaces from PerPolicyData results
+func authorizedPolicyData(req *http.Request, data []*reporting.Validation) []*reporting.Validation {
+	list := make([]*reporting.Validation, 0, len(data))
+	for _, validation := range data {
+		groups := make(map[string]*reporting.Group, len(validation.Groups))
+		for namespace, group := range validation.Groups {
+			if namespaceAllowed(req, namespace) {
+				groups[namespace] = group
+			}
+		}
+
+		if len(groups) == 0 {
+			continue
+		}
+
+		validation.Groups = groups
+		list = append(list, validation)
+	}
+
+	return list
+}
+
+// authorizedNamespaceData removes all not accessible namespaces from PerNamespaceData results
+func authorizedNamespaceData(req *http.Request, data []*reporting.Validation) []*reporting.Validation {
+	list := make([]*reporting.Validation, 0, len(data))
+	for _, validation := range data {
+		if namespaceAllowed(req, validation.Name) {
+			list = append(list, validation)
+		}
+	}
+
+	return list
+}
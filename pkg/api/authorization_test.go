@@ -0,0 +1,191 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authzv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting"
+)
+
+func withUser(name string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(api.WithUser(r.Context(), &api.User{Name: name})))
+	}
+}
+
+func Test_StaticNamespaceAuthorizer(t *testing.T) {
+	authorizer := api.NewStaticNamespaceAuthorizer(map[string][]string{
+		"tenant": {"team-a", "shared-*"},
+		"admin":  {"*"},
+	})
+
+	tests := []struct {
+		user      string
+		namespace string
+		allowed   bool
+	}{
+		{"tenant", "team-a", true},
+		{"tenant", "shared-tools", true},
+		{"tenant", "team-b", false},
+		{"tenant", "", false},
+		{"admin", "team-b", true},
+		{"admin", "", true},
+		{"unknown", "team-a", false},
+	}
+
+	for _, test := range tests {
+		allowed, err := authorizer.Allowed(context.Background(), &api.User{Name: test.user}, test.namespace)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if allowed != test.allowed {
+			t.Errorf("%s access to namespace '%s': got %v want %v", test.user, test.namespace, allowed, test.allowed)
+		}
+	}
+}
+
+func Test_SubjectAccessNamespaceAuthorizer(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authzv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+
+		review.Status.Allowed = attributes != nil &&
+			attributes.Resource == "namespaces" &&
+			attributes.Verb == "get" &&
+			attributes.Name == "team-a"
+
+		return true, review, nil
+	})
+
+	auth := api.NewKubernetesAuth(client.AuthenticationV1().TokenReviews(), client.AuthorizationV1().SubjectAccessReviews(), nil, time.Minute)
+	authorizer := api.NewSubjectAccessNamespaceAuthorizer(auth)
+
+	for namespace, expected := range map[string]bool{"team-a": true, "team-b": false, "": false} {
+		allowed, err := authorizer.Allowed(context.Background(), &api.User{Name: "tenant"}, namespace)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if allowed != expected {
+			t.Errorf("access to namespace '%s': got %v want %v", namespace, allowed, expected)
+		}
+	}
+}
+
+func Test_NamespaceAuthorization(t *testing.T) {
+	authorizer := api.NewStaticNamespaceAuthorizer(map[string][]string{"tenant": {"team-a"}})
+
+	store := kyverno.NewPolicyStore()
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "require-labels"})
+	store.Add(kyverno.Policy{Kind: "Policy", Name: "team-policy", Namespace: "team-a"})
+	store.Add(kyverno.Policy{Kind: "Policy", Name: "team-policy", Namespace: "team-b"})
+
+	t.Run("Policies", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/policies", nil)
+		rr := httptest.NewRecorder()
+
+		withUser("tenant", api.NamespaceAuthorization(authorizer, api.PolicyHandler(store)))(rr, req)
+
+		policies := make([]kyverno.Policy, 0)
+		if err := json.NewDecoder(rr.Body).Decode(&policies); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(policies) != 2 {
+			t.Fatalf("Expected the ClusterPolicy and the team-a Policy, got %d policies", len(policies))
+		}
+
+		for _, policy := range policies {
+			if policy.Namespace == "team-b" {
+				t.Error("Expected Policy of namespace team-b to be filtered")
+			}
+		}
+
+		if total := rr.Header().Get("X-Total-Count"); total != "2" {
+			t.Errorf("Unexpected X-Total-Count: %s", total)
+		}
+	})
+
+	t.Run("Policy Details", func(t *testing.T) {
+		handler := withUser("tenant", api.NamespaceAuthorization(authorizer, api.PolicyDetailHandler(store)))
+
+		for namespace, expected := range map[string]int{"team-a": http.StatusOK, "team-b": http.StatusNotFound} {
+			req := httptest.NewRequest("GET", "/policies/"+namespace+"/team-policy", nil)
+			req.SetPathValue("namespace", namespace)
+			req.SetPathValue("name", "team-policy")
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			if rr.Code != expected {
+				t.Errorf("namespace %s: got status %d want %d", namespace, rr.Code, expected)
+			}
+		}
+	})
+
+	t.Run("Policy Reporting", func(t *testing.T) {
+		authorizer := api.NewStaticNamespaceAuthorizer(map[string][]string{"kyverno-admin": {"kyverno"}})
+		handler := api.NamespaceAuthorization(authorizer, api.PolicyReportingHandler(&policyReportGeneratorStub{}, reportTemplates.Policy))
+
+		for user, expected := range map[string]int{"kyverno-admin": 1, "tenant": 0} {
+			req := httptest.NewRequest("GET", "/policy-details-reporting?format=json", nil)
+			rr := httptest.NewRecorder()
+
+			withUser(user, handler)(rr, req)
+
+			data := make([]*reporting.Validation, 0)
+			if err := json.NewDecoder(rr.Body).Decode(&data); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(data) != expected {
+				t.Errorf("%s: expected %d validations, got %d", user, expected, len(data))
+			}
+		}
+	})
+
+	t.Run("Namespace Reporting", func(t *testing.T) {
+		authorizer := api.NewStaticNamespaceAuthorizer(map[string][]string{"kyverno-admin": {"kyverno"}})
+		handler := api.NamespaceAuthorization(authorizer, api.NamespaceReportingHandler(&policyReportGeneratorStub{}, reportTemplates.Namespace))
+
+		for user, expected := range map[string]int{"kyverno-admin": 1, "tenant": 0} {
+			req := httptest.NewRequest("GET", "/namespace-details-reporting?format=json", nil)
+			rr := httptest.NewRecorder()
+
+			withUser(user, handler)(rr, req)
+
+			data := make([]*reporting.Validation, 0)
+			if err := json.NewDecoder(rr.Body).Decode(&data); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(data) != expected {
+				t.Errorf("%s: expected %d namespaces, got %d", user, expected, len(data))
+			}
+		}
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/policies", nil)
+		rr := httptest.NewRecorder()
+
+		api.NamespaceAuthorization(authorizer, api.PolicyHandler(store))(rr, req)
+
+		if total := rr.Header().Get("X-Total-Count"); total != "1" {
+			t.Errorf("Expected only the ClusterPolicy without authenticated user, got %s", total)
+		}
+	})
+}
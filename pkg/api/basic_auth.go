@@ -1,36 +1,218 @@
 package api
 
 import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"crypto/subtle"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Endpoint groups a BasicAuth user can be scoped to
+const (
+	ScopeMetrics    = "metrics"
+	ScopePolicies   = "policies"
+	ScopeReporting  = "reporting"
+	ScopeViolations = "violations"
 )
 
+// Scopes contains all available endpoint groups
+var Scopes = []string{ScopeMetrics, ScopePolicies, ScopeReporting, ScopeViolations}
+
 type BasicAuth struct {
 	Username string
 	Password string
+	// PasswordHash is a bcrypt hash of the password, used instead of Password if set
+	PasswordHash string
+	// Scopes restricts the user to the given endpoint groups, all groups are allowed if empty
+	Scopes []string
+}
+
+// Allowed checks if the user has access to the endpoint group
+func (a BasicAuth) Allowed(scope string) bool {
+	if len(a.Scopes) == 0 {
+		return true
+	}
+
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a BasicAuth) verify(password string) bool {
+	if a.PasswordHash != "" {
+		return bcrypt.CompareHashAndPassword([]byte(a.PasswordHash), []byte(password)) == nil
+	}
+
+	passwordHash := sha256.Sum256([]byte(password))
+	expectedPasswordHash := sha256.Sum256([]byte(a.Password))
+
+	return subtle.ConstantTimeCompare(passwordHash[:], expectedPasswordHash[:]) == 1
+}
+
+// dummyHash is compared for unknown users, so the response time does not reveal existing usernames
+var dummyHash = []byte("$2a$10$qwOaq8DDRoKx4zXWYfJhEerGHw5hHkkb6c1SGGmnnvjI8tU3.eP96")
+
+// credentialSet caches the digest of the last successfully verified password per user,
+// the digest key is random per set so the cache never holds plain password hashes
+type credentialSet struct {
+	users    map[string]BasicAuth
+	key      []byte
+	verified sync.Map
 }
 
-func HTTPBasic(auth *BasicAuth, next http.HandlerFunc) http.HandlerFunc {
+func (s *credentialSet) digest(password string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(password))
+
+	return mac.Sum(nil)
+}
+
+// Credentials of all BasicAuth users, the users can be replaced at runtime
+type Credentials struct {
+	set atomic.Pointer[credentialSet]
+}
+
+// Authenticate returns the BasicAuth user of valid credentials
+func (c *Credentials) Authenticate(username, password string) (BasicAuth, bool) {
+	set := c.set.Load()
+
+	user, ok := set.users[username]
+	if !ok {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return BasicAuth{}, false
+	}
+
+	digest := set.digest(password)
+	if cached, ok := set.verified.Load(username); ok && hmac.Equal(cached.([]byte), digest) {
+		return user, true
+	}
+
+	if !user.verify(password) {
+		return BasicAuth{}, false
+	}
+
+	set.verified.Store(username, digest)
+
+	return user, true
+}
+
+// Len returns the number of users
+func (c *Credentials) Len() int {
+	return len(c.set.Load().users)
+}
+
+// Update replaces all users at once and clears the verification cache, users without password are ignored
+func (c *Credentials) Update(users ...BasicAuth) {
+	list := make(map[string]BasicAuth, len(users))
+	for _, user := range users {
+		if user.Username == "" || (user.Password == "" && user.PasswordHash == "") {
+			continue
+		}
+
+		list[user.Username] = user
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("failed to generate credential cache key: %s", err))
+	}
+
+	c.set.Store(&credentialSet{users: list, key: key})
+}
+
+// NewCredentials constructor for Credentials, users without password are ignored
+func NewCredentials(users ...BasicAuth) *Credentials {
+	credentials := &Credentials{}
+	credentials.Update(users...)
+
+	return credentials
+}
+
+// ParseHtpasswd reads users from an htpasswd file with bcrypt hashes.
+// An optional third field restricts the user to a comma separated list of scopes: "user:hash:metrics,policies"
+func ParseHtpasswd(r io.Reader) ([]BasicAuth, error) {
+	users := make([]BasicAuth, 0)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(entry, ":", 3)
+		if len(fields) < 2 || fields[0] == "" {
+			return nil, fmt.Errorf("invalid htpasswd entry in line %d", line)
+		}
+
+		if _, err := bcrypt.Cost([]byte(fields[1])); err != nil {
+			return nil, fmt.Errorf("unsupported password hash for user %s in line %d, only bcrypt is supported", fields[0], line)
+		}
+
+		user := BasicAuth{Username: fields[0], PasswordHash: fields[1]}
+		if len(fields) == 3 {
+			scopes, err := ParseScopes(strings.Split(fields[2], ","))
+			if err != nil {
+				return nil, fmt.Errorf("invalid scopes for user %s in line %d: %w", fields[0], line, err)
+			}
+
+			user.Scopes = scopes
+		}
+
+		users = append(users, user)
+	}
+
+	return users, scanner.Err()
+}
+
+// ParseScopes validates and normalizes the given endpoint groups
+func ParseScopes(values []string) ([]string, error) {
+	scopes := make([]string, 0, len(values))
+	for _, value := range values {
+		scope := strings.ToLower(strings.TrimSpace(value))
+		if scope == "" {
+			continue
+		}
+
+		if !(BasicAuth{Scopes: Scopes}).Allowed(scope) {
+			return nil, fmt.Errorf("unknown scope %s", value)
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, nil
+}
+
+// HTTPBasic authenticates the request and checks the access of the user to the endpoint group
+func HTTPBasic(auth *Credentials, scope string, next http.HandlerFunc) http.HandlerFunc {
 	if auth == nil {
 		return next
 	}
 
-	expectedUsernameHash := sha256.Sum256([]byte(auth.Username))
-	expectedPasswordHash := sha256.Sum256([]byte(auth.Password))
-
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		username, password, ok := r.BasicAuth()
 		if ok {
+			if user, valid := auth.Authenticate(username, password); valid {
+				if !user.Allowed(scope) {
+					http.Error(w, "Forbidden", http.StatusForbidden)
+					return
+				}
 
-			usernameHash := sha256.Sum256([]byte(username))
-			passwordHash := sha256.Sum256([]byte(password))
-
-			usernameMatch := (subtle.ConstantTimeCompare(usernameHash[:], expectedUsernameHash[:]) == 1)
-			passwordMatch := (subtle.ConstantTimeCompare(passwordHash[:], expectedPasswordHash[:]) == 1)
-
-			if usernameMatch && passwordMatch {
-				next.ServeHTTP(w, r)
+				next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), &User{Name: username})))
 				return
 			}
 		}
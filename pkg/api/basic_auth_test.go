@@ -0,0 +1,163 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
+)
+
+func Test_ParseHtpasswd(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("valid entries", func(t *testing.T) {
+		users, err := api.ParseHtpasswd(strings.NewReader("# comment\n\nadmin:" + string(hash) + "\nprometheus:" + string(hash) + ":metrics\n"))
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if len(users) != 2 {
+			t.Fatalf("Expected 2 users, got %d", len(users))
+		}
+
+		if users[0].Username != "admin" || len(users[0].Scopes) != 0 {
+			t.Errorf("Unexpected admin user: %+v", users[0])
+		}
+
+		if users[1].Username != "prometheus" || len(users[1].Scopes) != 1 || users[1].Scopes[0] != api.ScopeMetrics {
+			t.Errorf("Unexpected prometheus user: %+v", users[1])
+		}
+	})
+
+	for name, content := range map[string]string{
+		"missing hash":   "admin",
+		"no bcrypt hash": "admin:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=",
+		"unknown scope":  "admin:" + string(hash) + ":admin",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := api.ParseHtpasswd(strings.NewReader(content)); err == nil {
+				t.Error("Expected parse error")
+			}
+		})
+	}
+}
+
+func Test_HTTPBasic(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("scrape"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credentials := api.NewCredentials(
+		api.BasicAuth{Username: "admin", Password: "admin"},
+		api.BasicAuth{Username: "prometheus", PasswordHash: string(hash), Scopes: []string{api.ScopeMetrics}},
+		api.BasicAuth{Username: "empty"},
+	)
+
+	if credentials.Len() != 2 {
+		t.Errorf("Expected users without password to be ignored, got %d users", credentials.Len())
+	}
+
+	tests := []struct {
+		name     string
+		scope    string
+		username string
+		password string
+		status   int
+	}{
+		{name: "admin metrics", scope: api.ScopeMetrics, username: "admin", password: "admin", status: http.StatusOK},
+		{name: "admin reporting", scope: api.ScopeReporting, username: "admin", password: "admin", status: http.StatusOK},
+		{name: "prometheus metrics", scope: api.ScopeMetrics, username: "prometheus", password: "scrape", status: http.StatusOK},
+		{name: "prometheus reporting", scope: api.ScopeReporting, username: "prometheus", password: "scrape", status: http.StatusForbidden},
+		{name: "wrong password", scope: api.ScopeMetrics, username: "prometheus", password: "admin", status: http.StatusUnauthorized},
+		{name: "unknown user", scope: api.ScopeMetrics, username: "unknown", password: "admin", status: http.StatusUnauthorized},
+		{name: "no credentials", scope: api.ScopeMetrics, status: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := api.HTTPBasic(credentials, test.scope, func(w http.ResponseWriter, r *http.Request) {
+				if user, ok := api.UserFrom(r.Context()); !ok || user.Name != test.username {
+					t.Error("Expected authenticated user in request context")
+				}
+
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if test.username != "" {
+				req.SetBasicAuth(test.username, test.password)
+			}
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			if rr.Code != test.status {
+				t.Errorf("got status %d want %d", rr.Code, test.status)
+			}
+		})
+	}
+}
+
+func Test_CredentialsUpdate(t *testing.T) {
+	credentials := api.NewCredentials(api.BasicAuth{Username: "admin", Password: "old"})
+
+	credentials.Update(api.BasicAuth{Username: "admin", Password: "new"})
+
+	if _, ok := credentials.Authenticate("admin", "old"); ok {
+		t.Error("Expected the old password to be rejected after the update")
+	}
+
+	if _, ok := credentials.Authenticate("admin", "new"); !ok {
+		t.Error("Expected the new password to be accepted after the update")
+	}
+}
+
+func Test_CredentialsVerificationCache(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	credentials := api.NewCredentials(api.BasicAuth{Username: "admin", PasswordHash: string(hash)})
+
+	for i := 0; i < 2; i++ {
+		if _, ok := credentials.Authenticate("admin", "secret"); !ok {
+			t.Fatalf("Expected the password to be accepted on attempt %d", i+1)
+		}
+		if _, ok := credentials.Authenticate("admin", "wrong"); ok {
+			t.Fatalf("Expected a wrong password to be rejected on attempt %d", i+1)
+		}
+	}
+
+	rotated, _ := bcrypt.GenerateFromPassword([]byte("rotated"), bcrypt.MinCost)
+	credentials.Update(api.BasicAuth{Username: "admin", PasswordHash: string(rotated)})
+
+	if _, ok := credentials.Authenticate("admin", "secret"); ok {
+		t.Error("Expected the cached password to be rejected after the update")
+	}
+	if _, ok := credentials.Authenticate("admin", "rotated"); !ok {
+		t.Error("Expected the new password to be accepted after the update")
+	}
+}
+
+func Test_CredentialsUnknownUserTiming(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+	credentials := api.NewCredentials(api.BasicAuth{Username: "admin", PasswordHash: string(hash)})
+
+	start := time.Now()
+	credentials.Authenticate("admin", "wrong")
+	known := time.Since(start)
+
+	start = time.Now()
+	credentials.Authenticate("unknown", "wrong")
+	unknown := time.Since(start)
+
+	if unknown < known/4 {
+		t.Errorf("Expected unknown users to be verified against a dummy hash, took %s compared to %s", unknown, known)
+	}
+}
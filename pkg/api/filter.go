@@ -0,0 +1,177 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+)
+
+// PolicyFilter restricts the Policies returned by the Policy REST API
+type PolicyFilter struct {
+	Kinds                    []string
+	Namespaces               []string
+	Categories               []string
+	Severities               []string
+	RuleTypes                []string
+	ValidationFailureActions []string
+	Background               *bool
+}
+
+// Pagination of a Policy list
+type Pagination struct {
+	Limit  int
+	Offset int
+}
+
+// NewPolicyFilter maps query parameters into a PolicyFilter. All list filters use plural
+// parameter names and can be repeated: kinds, namespaces, categories, severities, types
+// and validationFailureActions. Actions are compared case-insensitive with Audit as default.
+func NewPolicyFilter(query url.Values) (PolicyFilter, error) {
+	filter := PolicyFilter{
+		Kinds:                    query["kinds"],
+		Namespaces:               query["namespaces"],
+		Categories:               query["categories"],
+		Severities:               query["severities"],
+		RuleTypes:                query["types"],
+		ValidationFailureActions: normalizeActions(query["validationFailureActions"]),
+	}
+
+	if value := query.Get("background"); value != "" {
+		background, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid background value: %s", value)
+		}
+
+		filter.Background = &background
+	}
+
+	return filter, nil
+}
+
+// NewPagination maps the limit and offset query parameters into a Pagination
+func NewPagination(query url.Values) (Pagination, error) {
+	pagination := Pagination{}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return pagination, fmt.Errorf("invalid limit value: %s", value)
+		}
+
+		pagination.Limit = limit
+	}
+
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return pagination, fmt.Errorf("invalid offset value: %s", value)
+		}
+
+		pagination.Offset = offset
+	}
+
+	return pagination, nil
+}
+
+// Matches checks if the given Policy fulfills all configured criteria
+func (f PolicyFilter) Matches(policy kyverno.Policy) bool {
+	if !includes(policy.Kind, f.Kinds) {
+		return false
+	}
+	if !includes(policy.Namespace, f.Namespaces) {
+		return false
+	}
+	if !includes(policy.Category, f.Categories) {
+		return false
+	}
+	if !includes(policy.Severity, f.Severities) {
+		return false
+	}
+	if !includes(kyverno.NormalizeAction(policy.ValidationFailureAction), f.ValidationFailureActions) {
+		return false
+	}
+
+	if f.Background != nil {
+		// Kyverno applies background scans by default
+		background := policy.Background == nil || *policy.Background
+		if background != *f.Background {
+			return false
+		}
+	}
+
+	if len(f.RuleTypes) > 0 {
+		return slices.ContainsFunc(policy.Rules, func(rule *kyverno.Rule) bool {
+			return includes(rule.Type, f.RuleTypes)
+		})
+	}
+
+	return true
+}
+
+// Apply returns all matching Policies sorted by namespace and name
+func (f PolicyFilter) Apply(policies []kyverno.Policy) []kyverno.Policy {
+	list := make([]kyverno.Policy, 0, len(policies))
+	for _, policy := range policies {
+		if f.Matches(policy) {
+			list = append(list, policy)
+		}
+	}
+
+	SortPolicies(list)
+
+	return list
+}
+
+// Apply returns the requested page of the given list
+func (p Pagination) Apply(policies []kyverno.Policy) []kyverno.Policy {
+	if p.Offset >= len(policies) {
+		return []kyverno.Policy{}
+	}
+
+	policies = policies[p.Offset:]
+	if p.Limit > 0 && p.Limit < len(policies) {
+		policies = policies[:p.Limit]
+	}
+
+	return policies
+}
+
+// SortPolicies in a stable order, ClusterPolicies first, followed by namespace and name
+func SortPolicies(policies []kyverno.Policy) {
+	slices.SortStableFunc(policies, func(a, b kyverno.Policy) int {
+		if a.Namespace != b.Namespace {
+			return strings.Compare(a.Namespace, b.Namespace)
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// normalizeActions maps the deprecated lowercase actions, unknown values are kept and never match
+func normalizeActions(values []string) []string {
+	actions := make([]string, 0, len(values))
+	for _, value := range values {
+		if strings.EqualFold(value, kyverno.ActionAudit) || strings.EqualFold(value, kyverno.ActionEnforce) {
+			value = kyverno.NormalizeAction(value)
+		}
+
+		actions = append(actions, value)
+	}
+
+	return actions
+}
+
+func includes(value string, values []string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
@@ -19,14 +19,25 @@ var gzPool = sync.Pool{
 type gzipResponseWriter struct {
 	io.Writer
 	http.ResponseWriter
+	bodyless bool
 }
 
 func (w *gzipResponseWriter) WriteHeader(status int) {
+	// responses without body must not announce or contain a gzip stream
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		w.bodyless = true
+		w.Header().Del("Content-Encoding")
+	}
+
 	w.Header().Del("Content-Length")
 	w.ResponseWriter.WriteHeader(status)
 }
 
 func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if w.bodyless {
+		return w.ResponseWriter.Write(b)
+	}
+
 	return w.Writer.Write(b)
 }
 
@@ -41,11 +52,20 @@ func Gzip(next http.HandlerFunc) http.HandlerFunc {
 		w.Header().Set("Content-Encoding", "gzip")
 
 		gz := gzPool.Get().(*gzip.Writer)
-		defer gzPool.Put(gz)
-
 		gz.Reset(w)
-		defer gz.Close()
 
-		next(&gzipResponseWriter{ResponseWriter: w, Writer: gz}, r)
+		gw := &gzipResponseWriter{ResponseWriter: w, Writer: gz}
+		defer func() {
+			if gw.bodyless {
+				// skip the gzip header and trailer of Close
+				gz.Reset(ioutil.Discard)
+			} else {
+				gz.Close()
+			}
+
+			gzPool.Put(gz)
+		}()
+
+		next(gw, r)
 	}
 }
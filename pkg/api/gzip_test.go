@@ -62,4 +62,31 @@ func Test_GzipCompression(t *testing.T) {
 			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
 		}
 	})
+	t.Run("Not Modified Response", func(t *testing.T) {
+		handler := api.Gzip(api.PolicyHandler(kyverno.NewPolicyStore()))
+
+		req, err := http.NewRequest("GET", "/policies", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Add("Accept-Encoding", "gzip")
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		req.Header.Set("If-None-Match", rr.Header().Get("ETag"))
+
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusNotModified {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotModified)
+		}
+		if encoding := rr.Header().Get("Content-Encoding"); encoding != "" {
+			t.Errorf("handler returned unexpected Content-Encoding: %s", encoding)
+		}
+		if rr.Body.Len() != 0 {
+			t.Errorf("handler returned unexpected body: %v", rr.Body.Bytes())
+		}
+	})
 }
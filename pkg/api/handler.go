@@ -1,15 +1,22 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
 	"net/http"
-	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/violation"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 var funcMap = template.FuncMap{
@@ -18,22 +25,41 @@ var funcMap = template.FuncMap{
 	},
 }
 
-// PolicyHandler for the PolicyReport REST API
-func PolicyReportingHandler(s reporting.PolicyReportGenerator, basePath string) http.HandlerFunc {
+const (
+	formatHTML = "html"
+	formatJSON = "json"
+	formatCSV  = "csv"
+	formatXLSX = "xlsx"
+	formatPDF  = "pdf"
+)
+
+// PolicyReportingHandler for the PolicyReport REST API
+func PolicyReportingHandler(s reporting.PolicyReportGenerator, tmpl *template.Template) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		data, err := s.PerPolicyData(req.Context(), reporting.Filter{
-			Namespaces:   req.URL.Query()["namespaces"],
-			Policies:     req.URL.Query()["policies"],
-			ClusterScope: req.URL.Query().Get("clusterScope") != "0",
-		})
+		data, err := s.PerPolicyData(req.Context(), reportingFilter(req))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		tmpl, err := template.New("policy-report-details.html").Funcs(funcMap).ParseFiles(path.Join(basePath, "policy-report-details.html"), path.Join(basePath, "mui.css"))
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		data = authorizedPolicyData(req, data)
+
+		switch format := reportingFormat(req); format {
+		case formatJSON:
+			writeJSON(w, data)
+			return
+		case formatCSV:
+			writeCSV(w, "policy-report", reporting.PolicyRows(data))
+			return
+		case formatXLSX:
+			writeXLSX(w, "policy-report", reporting.PolicyRows(data))
+			return
+		case formatPDF:
+			writePDF(w, "policy-report", func(w io.Writer) error { return reporting.WritePolicyPDF(w, data) })
+			return
+		case formatHTML:
+		default:
+			http.Error(w, fmt.Sprintf("format %s is not supported", format), http.StatusBadRequest)
 			return
 		}
 
@@ -44,21 +70,32 @@ func PolicyReportingHandler(s reporting.PolicyReportGenerator, basePath string)
 }
 
 // NamespaceReportingHandler for the NamespaceReport REST API
-func NamespaceReportingHandler(s reporting.PolicyReportGenerator, basePath string) http.HandlerFunc {
+func NamespaceReportingHandler(s reporting.PolicyReportGenerator, tmpl *template.Template) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		data, err := s.PerNamespaceData(req.Context(), reporting.Filter{
-			Namespaces:   req.URL.Query()["namespaces"],
-			Policies:     req.URL.Query()["policies"],
-			ClusterScope: req.URL.Query().Get("clusterScope") != "0",
-		})
+		data, err := s.PerNamespaceData(req.Context(), reportingFilter(req))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		tmpl, err := template.New("namespace-report-details.html").Funcs(funcMap).ParseFiles(path.Join(basePath, "namespace-report-details.html"), path.Join(basePath, "mui.css"))
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		data = authorizedNamespaceData(req, data)
+
+		switch format := reportingFormat(req); format {
+		case formatJSON:
+			writeJSON(w, data)
+			return
+		case formatCSV:
+			writeCSV(w, "namespace-report", reporting.NamespaceRows(data))
+			return
+		case formatXLSX:
+			writeXLSX(w, "namespace-report", reporting.NamespaceRows(data))
+			return
+		case formatPDF:
+			writePDF(w, "namespace-report", func(w io.Writer) error { return reporting.WriteNamespacePDF(w, data) })
+			return
+		case formatHTML:
+		default:
+			http.Error(w, fmt.Sprintf("format %s is not supported", format), http.StatusBadRequest)
 			return
 		}
 
@@ -72,9 +109,31 @@ func NamespaceReportingHandler(s reporting.PolicyReportGenerator, basePath strin
 func PolicyHandler(s *kyverno.PolicyStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+		filter, err := NewPolicyFilter(req.URL.Query())
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{ "message": "%s" }`, err.Error())
+			return
+		}
+
+		pagination, err := NewPagination(req.URL.Query())
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{ "message": "%s" }`, err.Error())
+			return
+		}
+
+		if notModified(w, req, s.Revision()) {
+			return
+		}
+
+		policies := filter.Apply(authorizedPolicies(req, s.List()))
+
+		w.Header().Set("X-Total-Count", strconv.Itoa(len(policies)))
 		w.WriteHeader(http.StatusOK)
 
-		policies := s.List()
+		policies = pagination.Apply(policies)
 		if len(policies) == 0 {
 			fmt.Fprint(w, "[]")
 
@@ -88,13 +147,48 @@ func PolicyHandler(s *kyverno.PolicyStore) http.HandlerFunc {
 	}
 }
 
+// PolicyDetailHandler for the Policy Detail REST API
+func PolicyDetailHandler(s *kyverno.PolicyStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+		name, namespace := req.PathValue("name"), req.PathValue("namespace")
+		id := (&kyverno.Policy{Name: name, Namespace: namespace}).GetID()
+
+		// the ID is a hash of name and namespace, different paths can collide
+		policy, ok := s.Get(id)
+		if ok && (policy.Name != name || policy.Namespace != namespace) {
+			ok = false
+		}
+
+		if !ok || (policy.Namespace != "" && !namespaceAllowed(req, policy.Namespace)) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{ "message": "policy not found" }`)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		if err := json.NewEncoder(w).Encode(policy); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, `{ "message": "%s" }`, err.Error())
+		}
+	}
+}
+
 // VerifyImageRulesHandler for the ImageVerify Policy REST API
 func VerifyImageRulesHandler(s *kyverno.PolicyStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+		if notModified(w, req, s.Revision()) {
+			return
+		}
+
 		w.WriteHeader(http.StatusOK)
 
-		policies := s.List()
+		policies := authorizedPolicies(req, s.List())
 		if len(policies) == 0 {
 			fmt.Fprint(w, "[]")
 
@@ -102,33 +196,42 @@ func VerifyImageRulesHandler(s *kyverno.PolicyStore) http.HandlerFunc {
 		}
 
 		verifyRules := make([]*VerifyImage, 0)
-		images := map[string]bool{}
 
 		for _, policy := range policies {
 			for _, rule := range policy.Rules {
-				if rule.VerifyImages == nil || len(rule.VerifyImages) == 0 {
-					continue
-				}
-
 				for _, verify := range rule.VerifyImages {
-					if _, ok := images[verify.Image]; ok {
-						continue
-					}
-
 					verifyRules = append(verifyRules, &VerifyImage{
-						Policy:       &Policy{Name: policy.Name, Namespace: policy.Namespace, UID: policy.UID},
-						Rule:         rule.Name,
-						Repository:   verify.Repository,
-						Image:        verify.Image,
-						Key:          verify.Key,
-						Attestations: verify.Attestations,
+						Policy:          &Policy{Name: policy.Name, Namespace: policy.Namespace, UID: policy.UID},
+						Rule:            rule.Name,
+						Repository:      verify.Repository,
+						Image:           verify.Image,
+						Key:             verify.Key,
+						ImageReferences: verify.ImageReferences,
+						Attestors:       verify.Attestors,
+						Attestations:    verify.Attestations,
+						MutateDigest:    verify.MutateDigest,
+						VerifyDigest:    verify.VerifyDigest,
+						Required:        verify.Required,
 					})
-
-					images[verify.Image] = true
 				}
 			}
 		}
 
+		sort.SliceStable(verifyRules, func(i, j int) bool {
+			a, b := verifyRules[i], verifyRules[j]
+			if a.Image != b.Image {
+				return a.Image < b.Image
+			}
+			if a.Policy.Namespace != b.Policy.Namespace {
+				return a.Policy.Namespace < b.Policy.Namespace
+			}
+			if a.Policy.Name != b.Policy.Name {
+				return a.Policy.Name < b.Policy.Name
+			}
+
+			return a.Rule < b.Rule
+		})
+
 		if err := json.NewEncoder(w).Encode(verifyRules); err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			fmt.Fprintf(w, `{ "message": "%s" }`, err.Error())
@@ -136,6 +239,228 @@ func VerifyImageRulesHandler(s *kyverno.PolicyStore) http.HandlerFunc {
 	}
 }
 
+// VerifyImageMatchHandler for the REST API of verifyImages rules applying to an image reference.
+// With the namespace query parameter, only ClusterPolicies and the Policies of the namespace apply.
+func VerifyImageMatchHandler(s *kyverno.PolicyStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+		image := strings.TrimSpace(req.URL.Query().Get("image"))
+		if image == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{ "message": "image query parameter is required" }`)
+			return
+		}
+
+		namespace := strings.TrimSpace(req.URL.Query().Get("namespace"))
+		if namespace != "" && !namespaceAllowed(req, namespace) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{ "message": "namespace not allowed" }`)
+			return
+		}
+
+		if notModified(w, req, s.Revision()) {
+			return
+		}
+
+		policies := authorizedPolicies(req, s.List())
+		if namespace != "" {
+			policies = namespacePolicies(policies, namespace)
+		}
+
+		writeJSON(w, ImageMatchResult{Image: image, Namespace: namespace, Matches: MatchImage(policies, image)})
+	}
+}
+
+// ImageCoverageHandler for the image signature coverage report of running Pods or workloads
+func ImageCoverageHandler(s *kyverno.PolicyStore, g reporting.ImageGenerator, tmpl *template.Template) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		source := strings.ToLower(req.URL.Query().Get("source"))
+		if source == "" {
+			source = reporting.ImageSourcePods
+		}
+
+		if source != reporting.ImageSourcePods && source != reporting.ImageSourceWorkloads {
+			http.Error(w, fmt.Sprintf("unknown source %q, expected %s or %s", source, reporting.ImageSourcePods, reporting.ImageSourceWorkloads), http.StatusBadRequest)
+			return
+		}
+
+		format := reportingFormat(req)
+		if format != formatJSON && format != formatHTML {
+			http.Error(w, fmt.Sprintf("format %s is not supported", format), http.StatusBadRequest)
+			return
+		}
+
+		namespaces, err := g.NamespaceImages(req.Context(), source, reportingFilter(req))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		allowed := make([]*reporting.NamespaceImages, 0, len(namespaces))
+		for _, ns := range namespaces {
+			if namespaceAllowed(req, ns.Name) {
+				allowed = append(allowed, ns)
+			}
+		}
+
+		report := ImageCoverage(source, s.List(), allowed)
+
+		if format == formatJSON {
+			writeJSON(w, report)
+			return
+		}
+
+		if err = tmpl.Execute(w, report); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// KindCoverageHandler for the matrix of rules per Kubernetes kind
+func KindCoverageHandler(s *kyverno.PolicyStore, tmpl *template.Template) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		format := reportingFormat(req)
+		if format != formatJSON && format != formatHTML {
+			http.Error(w, fmt.Sprintf("format %s is not supported", format), http.StatusBadRequest)
+			return
+		}
+
+		matrix := KindCoverageMatrix(authorizedPolicies(req, s.List()))
+
+		if kinds := req.URL.Query()["kinds"]; len(kinds) > 0 {
+			filtered := make([]*KindCoverage, 0, len(matrix))
+			for _, coverage := range matrix {
+				if reporting.Contains(coverage.Kind, kinds) {
+					filtered = append(filtered, coverage)
+				}
+			}
+
+			matrix = filtered
+		}
+
+		if format == formatJSON {
+			writeJSON(w, matrix)
+			return
+		}
+
+		if err := tmpl.Execute(w, matrix); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// AdmissionRiskHandler for the JSON and HTML report of risky failure policy and admission settings
+func AdmissionRiskHandler(s *kyverno.PolicyStore, tmpl *template.Template) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		format := reportingFormat(req)
+		if format != formatJSON && format != formatHTML {
+			http.Error(w, fmt.Sprintf("format %s is not supported", format), http.StatusBadRequest)
+			return
+		}
+
+		report := AdmissionRisks(authorizedPolicies(req, s.List()))
+
+		if format == formatJSON {
+			writeJSON(w, report)
+			return
+		}
+
+		if err := tmpl.Execute(w, report); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// NamespaceRulesHandler for the REST API of all rules applying to a namespace, identified by its name or labels.
+// Without labels query parameter, the labels of the namespace are resolved by the optional NamespaceLookup.
+func NamespaceRulesHandler(s *kyverno.PolicyStore, namespaces NamespaceLookup) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+		query := req.URL.Query()
+
+		namespaceLabels, err := labels.ConvertSelectorToLabelsMap(query.Get("labels"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{ "message": "invalid labels: %s" }`, err.Error())
+			return
+		}
+
+		namespace := strings.TrimSpace(query.Get("namespace"))
+		if namespace == "" {
+			namespace = namespaceLabels[namespaceNameLabel]
+		}
+
+		if namespace == "" && len(namespaceLabels) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{ "message": "namespace or labels query parameter is required" }`)
+			return
+		}
+
+		if !namespaceAllowed(req, namespace) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{ "message": "namespace not allowed" }`)
+			return
+		}
+
+		if !query.Has("labels") && namespaces != nil {
+			var ok bool
+			if namespaceLabels, ok = namespaces.NamespaceLabels(namespace); !ok {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{ "message": "namespace not found" }`)
+				return
+			}
+		}
+
+		if notModified(w, req, s.Revision()) {
+			return
+		}
+
+		writeJSON(w, ApplicableRules(authorizedPolicies(req, s.List()), namespace, namespaceLabelsFor(namespace, namespaceLabels)))
+	}
+}
+
+// ViolationHandler for the blocked PolicyViolation REST API
+func ViolationHandler(s *violation.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+		query := req.URL.Query()
+		filter := violation.Filter{
+			Namespaces: query["namespaces"],
+			Policies:   query["policies"],
+			Rules:      query["rules"],
+			Kinds:      query["kinds"],
+			Names:      query["names"],
+		}
+
+		for param, value := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+			if query.Get(param) == "" {
+				continue
+			}
+
+			t, err := time.Parse(time.RFC3339, query.Get(param))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, `{ "message": "invalid %s value, expected RFC3339 timestamp" }`, param)
+				return
+			}
+
+			*value = t
+		}
+
+		items := make([]violation.PolicyViolation, 0)
+		for _, pv := range s.List(filter) {
+			if namespaceAllowed(req, pv.Resource.Namespace) {
+				items = append(items, pv)
+			}
+		}
+
+		writeJSON(w, ViolationList{Items: items, Counts: violation.Aggregate(items)})
+	}
+}
+
 // HealthzHandler for the Liveness REST API
 func HealthzHandler(synced func() bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
@@ -165,3 +490,99 @@ func ReadyHandler() http.HandlerFunc {
 		fmt.Fprint(w, "{}")
 	}
 }
+
+// etagEpoch distinguishes the store revisions of different process runs
+var etagEpoch = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// notModified sets the store revision as ETag and responds with 304 if the client already has this revision
+func notModified(w http.ResponseWriter, req *http.Request, revision uint64) bool {
+	etag := fmt.Sprintf(`"%s-%d"`, etagEpoch, revision)
+
+	// weak ETag, the response content depends on the content encoding
+	w.Header().Set("ETag", "W/"+etag)
+	w.Header().Add("Vary", "Authorization")
+
+	for _, value := range strings.Split(req.Header.Get("If-None-Match"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" || strings.TrimPrefix(value, "W/") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+
+	return false
+}
+
+func reportingFilter(req *http.Request) reporting.Filter {
+	return reporting.Filter{
+		Namespaces:   req.URL.Query()["namespaces"],
+		Policies:     req.URL.Query()["policies"],
+		ClusterScope: req.URL.Query().Get("clusterScope") != "0",
+	}
+}
+
+// reportingFormat resolves the requested output format from the format query parameter or the Accept header
+func reportingFormat(req *http.Request) string {
+	if format := strings.ToLower(req.URL.Query().Get("format")); format != "" {
+		return format
+	}
+
+	accept := req.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "application/json"):
+		return formatJSON
+	case strings.Contains(accept, "text/csv"):
+		return formatCSV
+	case strings.Contains(accept, "spreadsheetml"):
+		return formatXLSX
+	case strings.Contains(accept, "application/pdf"):
+		return formatPDF
+	}
+
+	return formatHTML
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		zap.L().Error("failed to encode response", zap.Error(err))
+	}
+}
+
+func writeCSV(w http.ResponseWriter, name string, rows []reporting.Row) {
+	w.Header().Set("Content-Type", "text/csv; charset=UTF-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, name))
+	w.WriteHeader(http.StatusOK)
+
+	if err := reporting.WriteCSV(w, rows); err != nil {
+		zap.L().Error("failed to write csv report", zap.Error(err))
+	}
+}
+
+func writeXLSX(w http.ResponseWriter, name string, rows []reporting.Row) {
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.xlsx"`, name))
+	w.WriteHeader(http.StatusOK)
+
+	if err := reporting.WriteXLSX(w, rows); err != nil {
+		zap.L().Error("failed to write xlsx report", zap.Error(err))
+	}
+}
+
+func writePDF(w http.ResponseWriter, name string, render func(io.Writer) error) {
+	buffer := &bytes.Buffer{}
+	if err := render(buffer); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, name))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := buffer.WriteTo(w); err != nil {
+		zap.L().Error("failed to write pdf report", zap.Error(err))
+	}
+}
@@ -2,11 +2,11 @@ package api_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
-	"path"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +14,7 @@ import (
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/violation"
 )
 
 type policyReportGeneratorStub struct {
@@ -138,6 +139,311 @@ func Test_PolicyAPI(t *testing.T) {
 			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
 		}
 	})
+	t.Run("ValidationFailureActionOverrides", func(t *testing.T) {
+		store := kyverno.NewPolicyStore()
+		store.Add(kyverno.Policy{
+			Kind:                    "ClusterPolicy",
+			Name:                    "require-labels",
+			ValidationFailureAction: "Audit",
+			ValidationFailureActionOverrides: []*kyverno.ValidationFailureActionOverride{
+				{Action: "Enforce", Namespaces: []string{"prod"}},
+			},
+		})
+
+		rr := httptest.NewRecorder()
+		api.PolicyHandler(store)(rr, httptest.NewRequest("GET", "/policies", nil))
+
+		expected := `"validationFailureAction":"Audit","validationFailureActionOverrides":[{"action":"Enforce","namespaces":["prod"]}]`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+	})
+}
+
+func Test_ConditionalGET(t *testing.T) {
+	store := kyverno.NewPolicyStore()
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "require-labels"})
+
+	handlers := map[string]http.HandlerFunc{
+		"/policies":           api.PolicyHandler(store),
+		"/verify-image-rules": api.VerifyImageRulesHandler(store),
+	}
+
+	for path, handler := range handlers {
+		t.Run(path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler(rr, httptest.NewRequest("GET", path, nil))
+
+			etag := rr.Header().Get("ETag")
+			if rr.Code != http.StatusOK || etag == "" {
+				t.Fatalf("Expected 200 with ETag, got %d with ETag '%s'", rr.Code, etag)
+			}
+
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("If-None-Match", etag)
+
+			rr = httptest.NewRecorder()
+			handler(rr, req)
+
+			if rr.Code != http.StatusNotModified {
+				t.Errorf("Expected 304 for the current ETag, got %d", rr.Code)
+			}
+			if rr.Body.Len() != 0 {
+				t.Errorf("Expected empty body for 304, got %s", rr.Body.String())
+			}
+		})
+	}
+
+	t.Run("changed store", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		api.PolicyHandler(store)(rr, httptest.NewRequest("GET", "/policies", nil))
+		etag := rr.Header().Get("ETag")
+
+		store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "disallow-latest-tag"})
+
+		req := httptest.NewRequest("GET", "/policies", nil)
+		req.Header.Set("If-None-Match", etag)
+
+		rr = httptest.NewRecorder()
+		api.PolicyHandler(store)(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected 200 after a store change, got %d", rr.Code)
+		}
+		if rr.Header().Get("ETag") == etag {
+			t.Error("Expected a new ETag after a store change")
+		}
+	})
+}
+
+func Test_PolicyAPIFilter(t *testing.T) {
+	background := false
+
+	store := kyverno.NewPolicyStore()
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "require-labels", Severity: "medium", ValidationFailureAction: "Audit", Rules: []*kyverno.Rule{{Name: "check-labels", Type: "validation"}}})
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "add-labels", Severity: "low", Rules: []*kyverno.Rule{{Name: "add-labels", Type: "mutation"}}})
+	store.Add(kyverno.Policy{Kind: "Policy", Name: "require-ressources", Namespace: "test", Severity: "high", ValidationFailureAction: "Enforce", Background: &background, Rules: []*kyverno.Rule{{Name: "check-ressources", Type: "validation"}}})
+	store.Add(kyverno.Policy{Kind: "Policy", Name: "disallow-latest", Namespace: "test", Severity: "medium", ValidationFailureAction: "enforce", Rules: []*kyverno.Rule{{Name: "check-tag", Type: "validation"}}})
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+		total    string
+	}{
+		{name: "Sorted", query: "", expected: []string{"add-labels", "require-labels", "disallow-latest", "require-ressources"}, total: "4"},
+		{name: "Kind", query: "kinds=ClusterPolicy", expected: []string{"add-labels", "require-labels"}, total: "2"},
+		{name: "Namespace", query: "namespaces=test", expected: []string{"disallow-latest", "require-ressources"}, total: "2"},
+		{name: "Severity", query: "severities=medium&severities=low", expected: []string{"add-labels", "require-labels", "disallow-latest"}, total: "3"},
+		{name: "RuleType", query: "types=mutation", expected: []string{"add-labels"}, total: "1"},
+		{name: "ValidationFailureAction", query: "validationFailureActions=enforce", expected: []string{"disallow-latest", "require-ressources"}, total: "2"},
+		{name: "Default ValidationFailureAction", query: "validationFailureActions=Audit", expected: []string{"add-labels", "require-labels"}, total: "2"},
+		{name: "Background", query: "background=false", expected: []string{"require-ressources"}, total: "1"},
+		{name: "Pagination", query: "limit=2&offset=1", expected: []string{"require-labels", "disallow-latest"}, total: "4"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/policies?"+test.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(api.PolicyHandler(store))
+
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusOK {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+			}
+
+			if total := rr.Header().Get("X-Total-Count"); total != test.total {
+				t.Errorf("handler returned unexpected total: got %v want %v", total, test.total)
+			}
+
+			policies := make([]kyverno.Policy, 0)
+			if err := json.NewDecoder(rr.Body).Decode(&policies); err != nil {
+				t.Fatal(err)
+			}
+
+			names := make([]string, 0, len(policies))
+			for _, p := range policies {
+				names = append(names, p.Name)
+			}
+
+			if strings.Join(names, ",") != strings.Join(test.expected, ",") {
+				t.Errorf("handler returned unexpected policies: got %v want %v", names, test.expected)
+			}
+		})
+	}
+
+	t.Run("Invalid Query", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/policies?limit=abc", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.PolicyHandler(store))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+		}
+	})
+}
+
+func Test_PolicyDetailAPI(t *testing.T) {
+	policy := kyverno.Policy{
+		Kind:              "Policy",
+		APIVersion:        "kyverno/v1",
+		Name:              "require-ressources",
+		Namespace:         "test",
+		Rules:             []*kyverno.Rule{{Name: "check-for-requests-and-limits", Type: "validation"}},
+		CreationTimestamp: time.Now(),
+		Content:           "apiVersion: kyverno/v1",
+	}
+
+	store := kyverno.NewPolicyStore()
+	store.Add(policy)
+
+	t.Run("Respose", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/policies/test/require-ressources", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.SetPathValue("namespace", "test")
+		req.SetPathValue("name", "require-ressources")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.PolicyDetailHandler(store))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		expected := `{"kind":"Policy","apiVersion":"kyverno/v1","name":"require-ressources","namespace":"test","background":null,"rules":[{"name":"check-for-requests-and-limits","type":"validation"}]`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+		if !strings.Contains(rr.Body.String(), `"content":"apiVersion: kyverno/v1"`) {
+			t.Errorf("handler returned unexpected body: got %v, expected policy content", rr.Body.String())
+		}
+	})
+
+	t.Run("Not Found Respose", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/policies/require-ressources", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.SetPathValue("name", "require-ressources")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.PolicyDetailHandler(store))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+		}
+	})
+	t.Run("Colliding ID", func(t *testing.T) {
+		// name and namespace are hashed as concatenation into the same ID
+		req, err := http.NewRequest("GET", "/policies/require-ressourcestest", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.SetPathValue("name", "require-ressourcestest")
+
+		if id := (&kyverno.Policy{Name: "require-ressourcestest"}).GetID(); id != policy.GetID() {
+			t.Fatalf("Expected colliding IDs, got %s and %s", id, policy.GetID())
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.PolicyDetailHandler(store))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+		}
+	})
+}
+
+func Test_ViolationAPI(t *testing.T) {
+	store := violation.NewStore(10)
+	store.Add(violation.PolicyViolation{Resource: violation.Resource{Kind: "Pod", Name: "nginx", Namespace: "default"}, Policy: violation.Policy{Name: "require-labels", Rule: "check-labels"}, Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)})
+	store.Add(violation.PolicyViolation{Resource: violation.Resource{Kind: "Pod", Name: "nginx", Namespace: "team-a"}, Policy: violation.Policy{Name: "require-labels", Rule: "check-labels"}, Timestamp: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)})
+	store.Add(violation.PolicyViolation{Resource: violation.Resource{Kind: "Deployment", Name: "api", Namespace: "team-a"}, Policy: violation.Policy{Name: "disallow-latest", Rule: "autogen-check-tag"}, Timestamp: time.Date(2024, 5, 8, 10, 0, 0, 0, time.UTC)})
+
+	t.Run("Respose", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/violations?namespaces=team-a&since=2024-05-01T12:00:00Z", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.ViolationHandler(store))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		list := api.ViolationList{}
+		if err := json.NewDecoder(rr.Body).Decode(&list); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(list.Items) != 2 || list.Items[0].Resource.Name != "api" {
+			t.Errorf("handler returned unexpected items: %+v", list.Items)
+		}
+		if list.Counts.Total != 2 || list.Counts.Policies["disallow-latest"] != 1 || list.Counts.Kinds["Pod"] != 1 {
+			t.Errorf("handler returned unexpected counts: %+v", list.Counts)
+		}
+	})
+
+	t.Run("Namespace Authorization", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/violations?namespaces=team-a&namespaces=default", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := withUser("dev", api.NamespaceAuthorization(api.NewStaticNamespaceAuthorizer(map[string][]string{"dev": {"default"}}), api.ViolationHandler(store)))
+
+		handler.ServeHTTP(rr, req)
+
+		list := api.ViolationList{}
+		if err := json.NewDecoder(rr.Body).Decode(&list); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(list.Items) != 1 || list.Items[0].Resource.Namespace != "default" || list.Counts.Total != 1 {
+			t.Errorf("handler should only return violations of accessible namespaces: %+v", list)
+		}
+	})
+
+	t.Run("Invalid Time Range", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/violations?until=yesterday", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.ViolationHandler(store))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+		}
+	})
 }
 
 func Test_HealthzAPI(t *testing.T) {
@@ -301,13 +607,62 @@ func Test_VerifyImageRulesAPI(t *testing.T) {
 			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
 		}
 
-		expected := `{"policy":{"name":"check-image","namespace":"test"},"rule":"check-image","repository":"registry.io/signatures","image":"ghcr.io/kyverno/test-verify-image:*","key":"\n\t\t\t\t\t-----BEGIN PUBLIC KEY-----\n\t\t\t\t\tMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE8nXRh950IZbRj8Ra/N9sbqOPZrfM\n\t\t\t\t\t5/KAQN0/KjHcorm/J5yctVd7iEcnessRQjU917hmKO6JWVGHpDguIyakZA==\n\t\t\t\t\t-----END PUBLIC KEY----- \n\t\t\t\t\t"}`
+		expected := `{"policy":{"name":"check-image","namespace":"test"},"rule":"check-image","repository":"registry.io/signatures","image":"ghcr.io/kyverno/test-verify-image:*","key":"\n\t\t\t\t\t-----BEGIN PUBLIC KEY-----\n\t\t\t\t\tMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE8nXRh950IZbRj8Ra/N9sbqOPZrfM\n\t\t\t\t\t5/KAQN0/KjHcorm/J5yctVd7iEcnessRQjU917hmKO6JWVGHpDguIyakZA==\n\t\t\t\t\t-----END PUBLIC KEY----- \n\t\t\t\t\t","mutateDigest":false,"verifyDigest":false,"required":false}`
 		if !strings.Contains(rr.Body.String(), expected) {
 			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
 		}
 	})
 }
 
+func Test_VerifyImageRulesAPIWithSharedImages(t *testing.T) {
+	count := 1
+	verify := &kyverno.VerifyImage{
+		ImageReferences: []string{"ghcr.io/kyverno/*"},
+		Attestors: []*kyverno.AttestorSet{{
+			Count: &count,
+			Entries: []*kyverno.Attestor{{
+				Keyless: &kyverno.KeylessAttestor{Subject: "https://github.com/kyverno/*", Issuer: "https://token.actions.githubusercontent.com", Rekor: "https://rekor.sigstore.dev"},
+			}},
+		}},
+		Attestations: []*kyverno.Attestation{{PredicateType: "https://slsa.dev/provenance/v0.2"}},
+		MutateDigest: true,
+		Required:     true,
+	}
+
+	store := kyverno.NewPolicyStore()
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "verify-signature", Rules: []*kyverno.Rule{{Name: "keyless", VerifyImages: []*kyverno.VerifyImage{verify}}}})
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "verify-provenance", Rules: []*kyverno.Rule{{Name: "slsa", VerifyImages: []*kyverno.VerifyImage{verify}}}})
+
+	rr := httptest.NewRecorder()
+	api.VerifyImageRulesHandler(store)(rr, httptest.NewRequest("GET", "/verify-image-rules", nil))
+
+	rules := make([]api.VerifyImage, 0)
+	if err := json.NewDecoder(rr.Body).Decode(&rules); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rules) != 2 {
+		t.Fatalf("Expected one entry per policy rule of the same image, got %d", len(rules))
+	}
+
+	if rules[0].Policy.Name != "verify-provenance" || rules[1].Policy.Name != "verify-signature" {
+		t.Errorf("Expected entries sorted by policy, got %s, %s", rules[0].Policy.Name, rules[1].Policy.Name)
+	}
+
+	entry := rules[1]
+	if !entry.MutateDigest || !entry.Required || entry.VerifyDigest {
+		t.Errorf("Unexpected digest flags: %+v", entry)
+	}
+
+	if len(entry.Attestors) != 1 || *entry.Attestors[0].Count != 1 || entry.Attestors[0].Entries[0].Keyless.Rekor != "https://rekor.sigstore.dev" {
+		t.Errorf("Unexpected attestors: %+v", entry.Attestors)
+	}
+
+	if len(entry.Attestations) != 1 || entry.Attestations[0].PredicateType != "https://slsa.dev/provenance/v0.2" {
+		t.Errorf("Unexpected attestations: %+v", entry.Attestations)
+	}
+}
+
 func Test_PolicyReportingHandler(t *testing.T) {
 	req, err := http.NewRequest("GET", "/policy-details-reporting", nil)
 	if err != nil {
@@ -315,7 +670,7 @@ func Test_PolicyReportingHandler(t *testing.T) {
 	}
 
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(api.PolicyReportingHandler(&policyReportGeneratorStub{}, path.Join("..", "..", "templates", "reporting")))
+	handler := http.HandlerFunc(api.PolicyReportingHandler(&policyReportGeneratorStub{}, reportTemplates.Policy))
 
 	handler.ServeHTTP(rr, req)
 
@@ -332,7 +687,7 @@ func Test_PolicyReportingHandlerDataError(t *testing.T) {
 	}
 
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(api.PolicyReportingHandler(&policyReportGeneratorStub{errors.New("error")}, path.Join("..", "..", "templates", "reporting")))
+	handler := http.HandlerFunc(api.PolicyReportingHandler(&policyReportGeneratorStub{errors.New("error")}, reportTemplates.Policy))
 
 	handler.ServeHTTP(rr, req)
 
@@ -348,7 +703,7 @@ func Test_NamespaceReportingHandler(t *testing.T) {
 	}
 
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(api.NamespaceReportingHandler(&policyReportGeneratorStub{}, path.Join("..", "..", "templates", "reporting")))
+	handler := http.HandlerFunc(api.NamespaceReportingHandler(&policyReportGeneratorStub{}, reportTemplates.Namespace))
 
 	handler.ServeHTTP(rr, req)
 
@@ -365,7 +720,7 @@ func Test_NamespaceReportingHandlerDataError(t *testing.T) {
 	}
 
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(api.NamespaceReportingHandler(&policyReportGeneratorStub{errors.New("error")}, path.Join("..", "..", "templates", "reporting")))
+	handler := http.HandlerFunc(api.NamespaceReportingHandler(&policyReportGeneratorStub{errors.New("error")}, reportTemplates.Namespace))
 
 	handler.ServeHTTP(rr, req)
 
@@ -373,3 +728,143 @@ func Test_NamespaceReportingHandlerDataError(t *testing.T) {
 		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusInternalServerError)
 	}
 }
+
+func Test_ReportingHandlerJSON(t *testing.T) {
+	t.Run("Policy Reporting", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/policy-details-reporting?format=json", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.PolicyReportingHandler(&policyReportGeneratorStub{}, reportTemplates.Policy))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		data := make([]*reporting.Validation, 0)
+		if err := json.NewDecoder(rr.Body).Decode(&data); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(data) != 1 || data[0].Groups["kyverno"].Rules["adding-capabilities"].Summary.Pass != 1 {
+			t.Errorf("handler returned unexpected data: %+v", data)
+		}
+	})
+
+	t.Run("Namespace Reporting", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/namespace-details-reporting", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept", "application/json")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.NamespaceReportingHandler(&policyReportGeneratorStub{}, reportTemplates.Namespace))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		expected := `[{"name":"kyverno","groups":{"disallow-capabilities":{"name":"disallow-capabilities","policy":{"title":"Disallow Capabilities"`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+	})
+}
+
+func Test_ReportingHandlerExport(t *testing.T) {
+	t.Run("CSV", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/policy-details-reporting?format=csv", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.PolicyReportingHandler(&policyReportGeneratorStub{}, reportTemplates.Policy))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		expected := "kyverno,disallow-capabilities,Disallow Capabilities,Pod Security Standards (Baseline),medium,adding-capabilities,Deployment,apps/v1,kyverno,pass"
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/namespace-details-reporting?format=xlsx", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.NamespaceReportingHandler(&policyReportGeneratorStub{}, reportTemplates.Namespace))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		if disposition := rr.Header().Get("Content-Disposition"); disposition != `attachment; filename="namespace-report.xlsx"` {
+			t.Errorf("handler returned unexpected Content-Disposition: %s", disposition)
+		}
+	})
+
+	t.Run("PDF", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/policy-details-reporting", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept", "application/pdf")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.PolicyReportingHandler(&policyReportGeneratorStub{}, reportTemplates.Policy))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		if contentType := rr.Header().Get("Content-Type"); contentType != "application/pdf" {
+			t.Errorf("handler returned unexpected Content-Type: %s", contentType)
+		}
+	})
+}
+
+func Test_ReportingHandlerUnsupportedFormat(t *testing.T) {
+	handlers := map[string]http.HandlerFunc{
+		"/policy-details-reporting":    api.PolicyReportingHandler(&policyReportGeneratorStub{}, reportTemplates.Policy),
+		"/namespace-details-reporting": api.NamespaceReportingHandler(&policyReportGeneratorStub{}, reportTemplates.Namespace),
+	}
+
+	for path, handler := range handlers {
+		t.Run(path, func(t *testing.T) {
+			req, err := http.NewRequest("GET", path+"?format=yaml", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusBadRequest {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+			}
+
+			if expected := "format yaml is not supported"; !strings.Contains(rr.Body.String(), expected) {
+				t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+			}
+		})
+	}
+}
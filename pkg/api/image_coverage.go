@@ -0,0 +1,72 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting"
+)
+
+// CoveredImage is an image used in a namespace with all verifyImages rules matching it
+type CoveredImage struct {
+	Image   string                 `json:"image"`
+	Usages  []reporting.ImageUsage `json:"usages"`
+	Matches []*ImageMatch          `json:"matches,omitempty"`
+}
+
+// ImageCoverageSummary counts the distinct covered and uncovered images
+type ImageCoverageSummary struct {
+	Covered   int `json:"covered"`
+	Uncovered int `json:"uncovered"`
+}
+
+// NamespaceImageCoverage of all images used in a namespace
+type NamespaceImageCoverage struct {
+	Name      string               `json:"name"`
+	Summary   ImageCoverageSummary `json:"summary"`
+	Covered   []*CoveredImage      `json:"covered"`
+	Uncovered []*CoveredImage      `json:"uncovered"`
+}
+
+// ImageCoverageReport of the image signature verification coverage per namespace
+type ImageCoverageReport struct {
+	Source     string                    `json:"source"`
+	Summary    ImageCoverageSummary      `json:"summary"`
+	Namespaces []*NamespaceImageCoverage `json:"namespaces"`
+}
+
+// ImageCoverage matches the images of each namespace against the verifyImages rules of all ClusterPolicies
+// and the Policies of the same namespace. Match and exclude blocks of the rules are not evaluated.
+func ImageCoverage(source string, policies []kyverno.Policy, namespaces []*reporting.NamespaceImages) *ImageCoverageReport {
+	report := &ImageCoverageReport{Source: source, Namespaces: make([]*NamespaceImageCoverage, 0, len(namespaces))}
+
+	for _, ns := range namespaces {
+		applicable := namespacePolicies(policies, ns.Name)
+
+		coverage := &NamespaceImageCoverage{Name: ns.Name, Covered: make([]*CoveredImage, 0), Uncovered: make([]*CoveredImage, 0)}
+
+		for image, usages := range ns.Images {
+			item := &CoveredImage{Image: image, Usages: usages, Matches: MatchImage(applicable, image)}
+			if len(item.Matches) > 0 {
+				coverage.Covered = append(coverage.Covered, item)
+			} else {
+				coverage.Uncovered = append(coverage.Uncovered, item)
+			}
+		}
+
+		sortCoveredImages(coverage.Covered)
+		sortCoveredImages(coverage.Uncovered)
+
+		coverage.Summary = ImageCoverageSummary{Covered: len(coverage.Covered), Uncovered: len(coverage.Uncovered)}
+		report.Summary.Covered += coverage.Summary.Covered
+		report.Summary.Uncovered += coverage.Summary.Uncovered
+
+		report.Namespaces = append(report.Namespaces, coverage)
+	}
+
+	return report
+}
+
+func sortCoveredImages(images []*CoveredImage) {
+	sort.Slice(images, func(i, j int) bool { return images[i].Image < images[j].Image })
+}
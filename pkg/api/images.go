@@ -0,0 +1,192 @@
+package api
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/wildcard"
+)
+
+const defaultRegistry = "docker.io"
+
+// KeylessIdentity is a subject and issuer pair of a keyless attestor
+type KeylessIdentity struct {
+	Subject string `json:"subject,omitempty"`
+	Issuer  string `json:"issuer,omitempty"`
+	Rekor   string `json:"rekor,omitempty"`
+}
+
+// ImageMatch is a verifyImages rule which applies to an image
+type ImageMatch struct {
+	Policy       *Policy                `json:"policy"`
+	Rule         string                 `json:"rule"`
+	Pattern      string                 `json:"pattern"`
+	Keys         []string               `json:"keys,omitempty"`
+	Identities   []KeylessIdentity      `json:"identities,omitempty"`
+	Attestors    []*kyverno.AttestorSet `json:"attestors,omitempty"`
+	Attestations []*kyverno.Attestation `json:"attestations,omitempty"`
+	MutateDigest bool                   `json:"mutateDigest"`
+	VerifyDigest bool                   `json:"verifyDigest"`
+	Required     bool                   `json:"required"`
+}
+
+// ImageMatchResult of all verifyImages rules for an image
+type ImageMatchResult struct {
+	Image     string        `json:"image"`
+	Namespace string        `json:"namespace,omitempty"`
+	Matches   []*ImageMatch `json:"matches"`
+}
+
+// namespacePolicies returns all ClusterPolicies and the Policies of the namespace
+func namespacePolicies(policies []kyverno.Policy, namespace string) []kyverno.Policy {
+	applicable := make([]kyverno.Policy, 0, len(policies))
+	for _, policy := range policies {
+		if policy.Namespace == "" || policy.Namespace == namespace {
+			applicable = append(applicable, policy)
+		}
+	}
+
+	return applicable
+}
+
+// MatchImage returns all verifyImages rules with an imageReference pattern matching the image
+func MatchImage(policies []kyverno.Policy, image string) []*ImageMatch {
+	candidates := imageCandidates(image)
+	matches := make([]*ImageMatch, 0)
+
+	for _, policy := range policies {
+		for _, rule := range policy.Rules {
+			for _, verify := range rule.VerifyImages {
+				pattern, ok := matchImageReferences(verify, candidates)
+				if !ok {
+					continue
+				}
+
+				keys, identities := attestorRequirements(verify)
+
+				matches = append(matches, &ImageMatch{
+					Policy:       &Policy{Name: policy.Name, Namespace: policy.Namespace, UID: policy.UID},
+					Rule:         rule.Name,
+					Pattern:      pattern,
+					Keys:         keys,
+					Identities:   identities,
+					Attestors:    verify.Attestors,
+					Attestations: verify.Attestations,
+					MutateDigest: verify.MutateDigest,
+					VerifyDigest: verify.VerifyDigest,
+					Required:     verify.Required,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Policy.Namespace != b.Policy.Namespace {
+			return a.Policy.Namespace < b.Policy.Namespace
+		}
+		if a.Policy.Name != b.Policy.Name {
+			return a.Policy.Name < b.Policy.Name
+		}
+
+		return a.Rule < b.Rule
+	})
+
+	return matches
+}
+
+func matchImageReferences(verify *kyverno.VerifyImage, candidates []string) (string, bool) {
+	patterns := verify.ImageReferences
+	if len(patterns) == 0 && verify.Image != "" {
+		patterns = []string{verify.Image}
+	}
+
+	for _, candidate := range candidates {
+		if pattern, ok := wildcard.MatchAny(patterns, candidate); ok {
+			return pattern, true
+		}
+	}
+
+	return "", false
+}
+
+// imageCandidates returns the image reference as written and in its normalized forms
+// with default registry, library repository and latest tag
+func imageCandidates(image string) []string {
+	name, suffix := image, ""
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, suffix = name[:i], name[i:]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, suffix = name[:i], name[i:]
+	}
+
+	names := []string{name}
+
+	parts := strings.SplitN(name, "/", 2)
+	switch {
+	case len(parts) == 1:
+		// official images are only resolved as docker.io/library/<name>
+		names = append(names, defaultRegistry+"/library/"+name)
+	case !(strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost"):
+		names = append(names, defaultRegistry+"/"+name)
+	}
+
+	suffixes := []string{suffix}
+	if suffix == "" {
+		suffixes = append(suffixes, ":latest")
+	}
+
+	candidates := []string{image}
+	seen := map[string]bool{image: true}
+
+	for _, n := range names {
+		for _, s := range suffixes {
+			if candidate := n + s; !seen[candidate] {
+				seen[candidate] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	return candidates
+}
+
+// attestorRequirements collects the keys and keyless identities of all image and attestation attestors
+func attestorRequirements(verify *kyverno.VerifyImage) ([]string, []KeylessIdentity) {
+	keys := make([]string, 0)
+	identities := make([]KeylessIdentity, 0)
+
+	var collect func(sets []*kyverno.AttestorSet)
+	collect = func(sets []*kyverno.AttestorSet) {
+		for _, set := range sets {
+			for _, entry := range set.Entries {
+				switch {
+				case entry.Keys != nil && entry.Keys.PublicKeys != "":
+					keys = append(keys, entry.Keys.PublicKeys)
+				case entry.Keys != nil && entry.Keys.KMS != "":
+					keys = append(keys, entry.Keys.KMS)
+				case entry.Keys != nil && entry.Keys.Secret != nil:
+					keys = append(keys, "secret:"+entry.Keys.Secret.Namespace+"/"+entry.Keys.Secret.Name)
+				case entry.Certificates != nil:
+					keys = append(keys, strings.TrimSpace(entry.Certificates.Certificate+"\n"+entry.Certificates.CertificateChain))
+				case entry.Keyless != nil:
+					identities = append(identities, KeylessIdentity{Subject: entry.Keyless.Subject, Issuer: entry.Keyless.Issuer, Rekor: entry.Keyless.Rekor})
+				case entry.Attestor != nil:
+					collect([]*kyverno.AttestorSet{entry.Attestor})
+				}
+			}
+		}
+	}
+
+	collect(verify.Attestors)
+	for _, attestation := range verify.Attestations {
+		collect(attestation.Attestors)
+	}
+
+	if verify.Key != "" && len(verify.Attestors) == 0 {
+		keys = append(keys, verify.Key)
+	}
+
+	return keys, identities
+}
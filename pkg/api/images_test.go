@@ -0,0 +1,260 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting"
+)
+
+func newImagePolicyStore() *kyverno.PolicyStore {
+	store := kyverno.NewPolicyStore()
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "verify-org", Rules: []*kyverno.Rule{{
+		Name: "keyless",
+		VerifyImages: []*kyverno.VerifyImage{{
+			ImageReferences: []string{"ghcr.io/org/*"},
+			Attestors: []*kyverno.AttestorSet{{Entries: []*kyverno.Attestor{
+				{Keyless: &kyverno.KeylessAttestor{Subject: "https://github.com/org/*", Issuer: "https://token.actions.githubusercontent.com"}},
+			}}},
+			Required: true,
+		}},
+	}}})
+	store.Add(kyverno.Policy{Kind: "Policy", Name: "verify-team", Namespace: "team", Rules: []*kyverno.Rule{{
+		Name: "keys",
+		VerifyImages: []*kyverno.VerifyImage{{
+			ImageReferences: []string{"ghcr.io/org/app:1.?"},
+			Attestations: []*kyverno.Attestation{{
+				PredicateType: "https://cyclonedx.org/bom",
+				Attestors: []*kyverno.AttestorSet{{Entries: []*kyverno.Attestor{
+					{Keys: &kyverno.StaticKeyAttestor{Secret: &kyverno.SecretReference{Name: "cosign", Namespace: "team"}}},
+				}}},
+			}},
+		}},
+	}}})
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "verify-hub", Rules: []*kyverno.Rule{{
+		Name: "library",
+		VerifyImages: []*kyverno.VerifyImage{{
+			ImageReferences: []string{"docker.io/library/nginx:latest"},
+			Attestors:       []*kyverno.AttestorSet{{Entries: []*kyverno.Attestor{{Keys: &kyverno.StaticKeyAttestor{PublicKeys: "public-key"}}}}},
+		}},
+	}}})
+
+	return store
+}
+
+func Test_MatchImage(t *testing.T) {
+	policies := newImagePolicyStore().List()
+
+	t.Run("wildcard patterns", func(t *testing.T) {
+		matches := api.MatchImage(policies, "ghcr.io/org/app:1.2")
+		if len(matches) != 2 {
+			t.Fatalf("Expected 2 matching rules, got %d", len(matches))
+		}
+
+		if matches[0].Policy.Name != "verify-org" || matches[0].Pattern != "ghcr.io/org/*" || !matches[0].Required {
+			t.Errorf("Unexpected first match: %+v", matches[0])
+		}
+		if len(matches[0].Identities) != 1 || matches[0].Identities[0].Subject != "https://github.com/org/*" {
+			t.Errorf("Expected keyless identity, got %+v", matches[0].Identities)
+		}
+
+		if matches[1].Policy.Name != "verify-team" || len(matches[1].Keys) != 1 || matches[1].Keys[0] != "secret:team/cosign" {
+			t.Errorf("Expected the secret key of the attestation, got %+v", matches[1])
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		if matches := api.MatchImage(policies, "ghcr.io/other/app:1.2"); len(matches) != 0 {
+			t.Errorf("Expected no matches, got %d", len(matches))
+		}
+	})
+
+	t.Run("normalized docker hub image", func(t *testing.T) {
+		matches := api.MatchImage(policies, "nginx")
+		if len(matches) != 1 || matches[0].Policy.Name != "verify-hub" || matches[0].Keys[0] != "public-key" {
+			t.Errorf("Expected match of the normalized image, got %+v", matches)
+		}
+	})
+
+	t.Run("non-canonical docker hub reference", func(t *testing.T) {
+		hub := []kyverno.Policy{{Kind: "ClusterPolicy", Name: "verify-short", Rules: []*kyverno.Rule{{
+			Name:         "short",
+			VerifyImages: []*kyverno.VerifyImage{{ImageReferences: []string{"docker.io/nginx*"}}},
+		}}}}
+
+		if matches := api.MatchImage(hub, "nginx"); len(matches) != 0 {
+			t.Errorf("Expected no match of docker.io/nginx, Kyverno resolves nginx as docker.io/library/nginx, got %+v", matches)
+		}
+	})
+}
+
+func Test_VerifyImageMatchAPI(t *testing.T) {
+	handler := api.VerifyImageMatchHandler(newImagePolicyStore())
+
+	t.Run("missing image", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/verify-image-rules/match", nil))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("matches", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/verify-image-rules/match?image=ghcr.io/org/app:1.2", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+
+		result := api.ImageMatchResult{}
+		if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Image != "ghcr.io/org/app:1.2" || len(result.Matches) != 2 {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		for namespace, expected := range map[string]int{"team": 2, "default": 1} {
+			rr := httptest.NewRecorder()
+			handler(rr, httptest.NewRequest("GET", "/verify-image-rules/match?image=ghcr.io/org/app:1.2&namespace="+namespace, nil))
+
+			result := api.ImageMatchResult{}
+			if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Namespace != namespace || len(result.Matches) != expected {
+				t.Errorf("Expected %d matches in namespace %s, got %+v", expected, namespace, result)
+			}
+		}
+	})
+
+	t.Run("namespace authorization", func(t *testing.T) {
+		secured := withUser("dev", api.NamespaceAuthorization(api.NewStaticNamespaceAuthorizer(map[string][]string{"dev": {"dev"}}), handler))
+
+		rr := httptest.NewRecorder()
+		secured(rr, httptest.NewRequest("GET", "/verify-image-rules/match?image=nginx&namespace=team", nil))
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusForbidden)
+		}
+	})
+}
+
+type imageGenerator struct {
+	namespaces []*reporting.NamespaceImages
+}
+
+func (g imageGenerator) NamespaceImages(_ context.Context, _ string, _ reporting.Filter) ([]*reporting.NamespaceImages, error) {
+	return g.namespaces, nil
+}
+
+var namespaceImages = []*reporting.NamespaceImages{
+	{Name: "default", Images: map[string][]reporting.ImageUsage{
+		"ghcr.io/org/app:1.2": {{Kind: "Pod", Name: "app", Container: "app"}},
+		"alpine":              {{Kind: "Pod", Name: "tools", Container: "tools"}},
+	}},
+	{Name: "team", Images: map[string][]reporting.ImageUsage{
+		"ghcr.io/org/app:1.2": {{Kind: "Pod", Name: "app", Container: "app"}},
+	}},
+}
+
+func Test_ImageCoverage(t *testing.T) {
+	report := api.ImageCoverage(reporting.ImageSourcePods, newImagePolicyStore().List(), namespaceImages)
+
+	if report.Summary.Covered != 2 || report.Summary.Uncovered != 1 {
+		t.Errorf("Unexpected summary: %+v", report.Summary)
+	}
+
+	defaultNS := report.Namespaces[0]
+	if len(defaultNS.Uncovered) != 1 || defaultNS.Uncovered[0].Image != "alpine" {
+		t.Errorf("Expected alpine as uncovered image, got %+v", defaultNS.Uncovered)
+	}
+	if len(defaultNS.Covered) != 1 || len(defaultNS.Covered[0].Matches) != 1 {
+		t.Errorf("Expected only the ClusterPolicy to cover the image in default, got %+v", defaultNS.Covered)
+	}
+
+	if team := report.Namespaces[1]; len(team.Covered) != 1 || len(team.Covered[0].Matches) != 2 {
+		t.Errorf("Expected the ClusterPolicy and the namespaced Policy to cover the image in team, got %+v", team.Covered)
+	}
+}
+
+func Test_ImageCoverageAPI(t *testing.T) {
+	handler := api.ImageCoverageHandler(newImagePolicyStore(), imageGenerator{namespaceImages}, reportTemplates.ImageCoverage)
+
+	t.Run("html", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/image-coverage-reporting", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+		if !strings.Contains(rr.Body.String(), "Kyverno Image Signature Coverage Report") || !strings.Contains(rr.Body.String(), "alpine") {
+			t.Error("Expected rendered coverage report")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/image-coverage-reporting?format=json&source=workloads", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+
+		report := api.ImageCoverageReport{}
+		if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+			t.Fatal(err)
+		}
+
+		if report.Source != reporting.ImageSourceWorkloads || len(report.Namespaces) != 2 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("namespace authorization", func(t *testing.T) {
+		authorizer := api.NewStaticNamespaceAuthorizer(map[string][]string{"dev": {"team"}})
+		secured := withUser("dev", api.NamespaceAuthorization(authorizer, handler))
+
+		rr := httptest.NewRecorder()
+		secured(rr, httptest.NewRequest("GET", "/image-coverage-reporting?format=json", nil))
+
+		report := api.ImageCoverageReport{}
+		if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(report.Namespaces) != 1 || report.Namespaces[0].Name != "team" {
+			t.Errorf("Expected only the team namespace, got %+v", report.Namespaces)
+		}
+	})
+
+	t.Run("invalid source", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/image-coverage-reporting?source=nodes", nil))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/image-coverage-reporting?format=pdf", nil))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,348 @@
+package api
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+)
+
+// defaultAutogenControllers are used by Kyverno for Pod rules without autogen-controllers annotation
+var defaultAutogenControllers = []string{"DaemonSet", "Deployment", "Job", "StatefulSet", "ReplicaSet", "ReplicationController", "CronJob"}
+
+const (
+	RuleTypeValidate     = "validate"
+	RuleTypeMutate       = "mutate"
+	RuleTypeGenerate     = "generate"
+	RuleTypeVerifyImages = "verifyImages"
+)
+
+// KindRule is a rule matching a kind. Excluded is set if an exclude block lists the kind without further conditions,
+// PartiallyExcluded if the exclusion is narrowed by names, namespaces, selectors or user info.
+// Autogen is set if the rule was generated by Kyverno for a Pod controller.
+type KindRule struct {
+	Policy            *Policy `json:"policy"`
+	Rule              string  `json:"rule"`
+	Type              string  `json:"type"`
+	Autogen           bool    `json:"autogen"`
+	Excluded          bool    `json:"excluded"`
+	PartiallyExcluded bool    `json:"partiallyExcluded"`
+}
+
+// KindSummary counts the matching rules per type
+type KindSummary struct {
+	Validate     int `json:"validate"`
+	Mutate       int `json:"mutate"`
+	Generate     int `json:"generate"`
+	VerifyImages int `json:"verifyImages"`
+}
+
+// KindCoverage of a Kubernetes kind or one of its subresources
+type KindCoverage struct {
+	Kind        string      `json:"kind"`
+	Subresource string      `json:"subresource,omitempty"`
+	Summary     KindSummary `json:"summary"`
+	Rules       []*KindRule `json:"rules"`
+}
+
+// KindCoverageMatrix inverts the match and exclude blocks of all rules into the rules per kind.
+// Kinds are grouped without group and version, the wildcard kind is listed as "*".
+// The supported Kyverno CRD version has no admission operations in its resource filters.
+func KindCoverageMatrix(policies []kyverno.Policy) []*KindCoverage {
+	mapping := make(map[string]*KindCoverage)
+
+	add := func(kind, subresource string, rule *KindRule) {
+		key := kind + "/" + subresource
+
+		coverage, ok := mapping[key]
+		if !ok {
+			coverage = &KindCoverage{Kind: kind, Subresource: subresource, Rules: make([]*KindRule, 0)}
+			mapping[key] = coverage
+		}
+
+		for _, existing := range coverage.Rules {
+			// the same rule can list a kind in multiple filters
+			if existing.Policy == rule.Policy && existing.Rule == rule.Rule {
+				return
+			}
+		}
+
+		coverage.Rules = append(coverage.Rules, rule)
+		switch rule.Type {
+		case RuleTypeValidate:
+			coverage.Summary.Validate++
+		case RuleTypeMutate:
+			coverage.Summary.Mutate++
+		case RuleTypeGenerate:
+			coverage.Summary.Generate++
+		case RuleTypeVerifyImages:
+			coverage.Summary.VerifyImages++
+		}
+	}
+
+	for _, policy := range policies {
+		ref := &Policy{Name: policy.Name, Namespace: policy.Namespace, UID: policy.UID}
+
+		for _, rule := range policy.Rules {
+			ruleType := kindRuleType(rule)
+			exclusions := kindExclusions(rule.Exclude)
+
+			for _, kind := range matchedKinds(rule.Match) {
+				name, subresource := parseKind(kind)
+				excluded, partially := exclusions.excludes(name, subresource)
+				add(name, subresource, &KindRule{
+					Policy:            ref,
+					Rule:              rule.Name,
+					Type:              ruleType,
+					Excluded:          excluded,
+					PartiallyExcluded: partially,
+				})
+			}
+
+			for _, autogen := range rule.Autogen {
+				autogenExclusions := kindExclusions(autogen.Exclude)
+
+				for _, kind := range matchedKinds(autogen.Match) {
+					name, subresource := parseKind(kind)
+					excluded, partially := autogenExclusions.excludes(name, subresource)
+					add(name, subresource, &KindRule{
+						Policy:            ref,
+						Rule:              autogen.Name,
+						Type:              ruleType,
+						Autogen:           true,
+						Excluded:          excluded,
+						PartiallyExcluded: partially,
+					})
+				}
+			}
+
+			// without autogen rules in the status the controllers are derived from the annotation
+			if len(rule.Autogen) > 0 {
+				continue
+			}
+
+			for _, controller := range autogenControllers(policy, rule) {
+				excluded, partially := exclusions.excludes(controller, "")
+				add(controller, "", &KindRule{
+					Policy:            ref,
+					Rule:              rule.Name,
+					Type:              ruleType,
+					Autogen:           true,
+					Excluded:          excluded,
+					PartiallyExcluded: partially,
+				})
+			}
+		}
+	}
+
+	list := make([]*KindCoverage, 0, len(mapping))
+	for _, coverage := range mapping {
+		sort.SliceStable(coverage.Rules, func(i, j int) bool {
+			a, b := coverage.Rules[i], coverage.Rules[j]
+			if a.Policy.Namespace != b.Policy.Namespace {
+				return a.Policy.Namespace < b.Policy.Namespace
+			}
+			if a.Policy.Name != b.Policy.Name {
+				return a.Policy.Name < b.Policy.Name
+			}
+
+			return a.Rule < b.Rule
+		})
+
+		list = append(list, coverage)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind < list[j].Kind
+		}
+
+		return list[i].Subresource < list[j].Subresource
+	})
+
+	return list
+}
+
+func kindRuleType(rule *kyverno.Rule) string {
+	if len(rule.VerifyImages) > 0 {
+		return RuleTypeVerifyImages
+	}
+
+	switch rule.Type {
+	case "mutation":
+		return RuleTypeMutate
+	case "generation":
+		return RuleTypeGenerate
+	}
+
+	return RuleTypeValidate
+}
+
+// matchedKinds returns the kinds of all any and all filters
+func matchedKinds(match *kyverno.MatchResources) []string {
+	if match == nil {
+		return nil
+	}
+
+	kinds := make([]string, 0)
+	for _, filter := range append(append([]*kyverno.ResourceFilter{}, match.Any...), match.All...) {
+		if filter.Resources != nil {
+			kinds = append(kinds, filter.Resources.Kinds...)
+		}
+	}
+
+	return kinds
+}
+
+// kindExclusion contains the kind/subresource keys of all excluded kinds, partial if the exclusion has further conditions
+type kindExclusion struct {
+	whole   map[string]bool
+	partial map[string]bool
+}
+
+func (e kindExclusion) excludes(kind, subresource string) (bool, bool) {
+	key := kind + "/" + subresource
+	if e.whole[key] || e.whole["*/"] {
+		return true, false
+	}
+
+	return false, e.partial[key] || e.partial["*/"]
+}
+
+// kindExclusions collects the excluded kinds. Filters of an all block only exclude the kinds
+// selected by every filter, as a whole only if none of the filters has further conditions.
+func kindExclusions(exclude *kyverno.MatchResources) kindExclusion {
+	exclusion := kindExclusion{whole: make(map[string]bool), partial: make(map[string]bool)}
+	if exclude == nil {
+		return exclusion
+	}
+
+	add := func(keys map[string]bool, whole bool) {
+		for key := range keys {
+			if whole {
+				exclusion.whole[key] = true
+			} else {
+				exclusion.partial[key] = true
+			}
+		}
+	}
+
+	for _, filter := range exclude.Any {
+		add(filterKinds(filter), kindsOnly(filter))
+	}
+
+	var intersection map[string]bool
+	allKindsOnly := true
+	for _, filter := range exclude.All {
+		allKindsOnly = allKindsOnly && kindsOnly(filter)
+
+		keys := filterKinds(filter)
+		if len(keys) == 0 || keys["*/"] {
+			// the filter selects every kind
+			continue
+		}
+
+		if intersection == nil {
+			intersection = keys
+			continue
+		}
+
+		for key := range intersection {
+			if !keys[key] {
+				delete(intersection, key)
+			}
+		}
+	}
+
+	add(intersection, allKindsOnly)
+
+	return exclusion
+}
+
+// filterKinds returns the kind/subresource keys of the filter
+func filterKinds(filter *kyverno.ResourceFilter) map[string]bool {
+	keys := make(map[string]bool)
+	if filter.Resources == nil {
+		return keys
+	}
+
+	for _, kind := range filter.Resources.Kinds {
+		name, subresource := parseKind(kind)
+		keys[name+"/"+subresource] = true
+	}
+
+	return keys
+}
+
+// kindsOnly reports whether the filter has no other resource or user conditions than kinds
+func kindsOnly(filter *kyverno.ResourceFilter) bool {
+	if len(filter.Roles) > 0 || len(filter.ClusterRoles) > 0 || len(filter.Subjects) > 0 {
+		return false
+	}
+
+	r := filter.Resources
+	if r == nil {
+		return true
+	}
+
+	return len(r.Names) == 0 && len(r.Namespaces) == 0 && len(r.Annotations) == 0 && r.Selector == nil && r.NamespaceSelector == nil
+}
+
+// parseKind splits Kyverno kind selectors like "apps/v1/Deployment/scale" or "Pod/exec" into kind and subresource
+func parseKind(selector string) (string, string) {
+	parts := strings.Split(selector, "/")
+
+	for i := len(parts) - 1; i >= 0; i-- {
+		if parts[i] == "*" || (parts[i] != "" && unicode.IsUpper(rune(parts[i][0]))) {
+			return parts[i], strings.Join(parts[i+1:], "/")
+		}
+	}
+
+	return selector, ""
+}
+
+// autogenControllers returns the Pod controllers Kyverno generates rules for. Kyverno only generates rules
+// for Pod rules without names, selectors or annotations in their match and exclude blocks.
+func autogenControllers(policy kyverno.Policy, rule *kyverno.Rule) []string {
+	controllers := defaultAutogenControllers
+	if len(policy.AutogenControllers) > 0 {
+		controllers = policy.AutogenControllers
+	}
+
+	if len(controllers) == 1 && strings.EqualFold(controllers[0], "none") {
+		return nil
+	}
+
+	kinds := matchedKinds(rule.Match)
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	for _, kind := range kinds {
+		if name, subresource := parseKind(kind); name != "Pod" || subresource != "" {
+			return nil
+		}
+	}
+
+	for _, match := range []*kyverno.MatchResources{rule.Match, rule.Exclude} {
+		if match == nil {
+			continue
+		}
+
+		for _, filter := range append(append([]*kyverno.ResourceFilter{}, match.Any...), match.All...) {
+			if r := filter.Resources; r != nil && (len(r.Names) > 0 || r.Selector != nil || len(r.Annotations) > 0) {
+				return nil
+			}
+		}
+	}
+
+	list := make([]string, 0, len(controllers))
+	for _, controller := range controllers {
+		if controller = strings.TrimSpace(controller); controller != "" {
+			list = append(list, controller)
+		}
+	}
+
+	return list
+}
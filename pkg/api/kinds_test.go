@@ -0,0 +1,227 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
+	apiV1 "github.com/kyverno/policy-reporter-kyverno-plugin/pkg/crd/api/kyverno/v1"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno/kubernetes"
+)
+
+func resources(kinds ...string) *kyverno.MatchResources {
+	return &kyverno.MatchResources{Any: []*kyverno.ResourceFilter{{Resources: &kyverno.ResourceDescription{Kinds: kinds}}}}
+}
+
+func newKindPolicyStore() *kyverno.PolicyStore {
+	store := kyverno.NewPolicyStore()
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "require-labels", UID: "1", AutogenControllers: []string{"Deployment", "CronJob"}, Rules: []*kyverno.Rule{
+		{Name: "check-team", Type: "validation", Match: resources("Pod"), Exclude: resources("Deployment")},
+	}})
+	store.Add(kyverno.Policy{Kind: "ClusterPolicy", Name: "ingress", UID: "2", Rules: []*kyverno.Rule{
+		{Name: "add-class", Type: "mutation", Match: resources("networking.k8s.io/v1/Ingress")},
+		{Name: "deny-exec", Type: "validation", Match: resources("Pod/exec")},
+	}})
+	store.Add(kyverno.Policy{Kind: "Policy", Name: "verify", Namespace: "team", UID: "3", AutogenControllers: []string{"none"}, Rules: []*kyverno.Rule{
+		{Name: "signed", Type: "validation", Match: resources("Pod"), VerifyImages: []*kyverno.VerifyImage{{ImageReferences: []string{"*"}}}},
+	}})
+
+	return store
+}
+
+func findKind(matrix []*api.KindCoverage, kind, subresource string) *api.KindCoverage {
+	for _, coverage := range matrix {
+		if coverage.Kind == kind && coverage.Subresource == subresource {
+			return coverage
+		}
+	}
+
+	return nil
+}
+
+func Test_KindCoverageMatrix(t *testing.T) {
+	matrix := api.KindCoverageMatrix(newKindPolicyStore().List())
+
+	if len(matrix) != 5 {
+		t.Fatalf("Expected CronJob, Deployment, Ingress, Pod and Pod/exec, got %d kinds", len(matrix))
+	}
+
+	pod := findKind(matrix, "Pod", "")
+	if pod == nil || pod.Summary.Validate != 1 || pod.Summary.VerifyImages != 1 {
+		t.Errorf("Unexpected Pod coverage: %+v", pod)
+	}
+
+	if ingress := findKind(matrix, "Ingress", ""); ingress == nil || ingress.Summary.Mutate != 1 || ingress.Rules[0].Rule != "add-class" {
+		t.Errorf("Expected Ingress without group and version, got %+v", ingress)
+	}
+
+	if exec := findKind(matrix, "Pod", "exec"); exec == nil || exec.Rules[0].Rule != "deny-exec" {
+		t.Errorf("Expected Pod/exec subresource, got %+v", exec)
+	}
+
+	deployment := findKind(matrix, "Deployment", "")
+	if deployment == nil || len(deployment.Rules) != 1 || !deployment.Rules[0].Autogen || !deployment.Rules[0].Excluded {
+		t.Errorf("Expected excluded autogen Deployment rule, got %+v", deployment)
+	}
+
+	if cronjob := findKind(matrix, "CronJob", ""); cronjob == nil || cronjob.Rules[0].Excluded || cronjob.Rules[0].Policy.Name != "require-labels" {
+		t.Errorf("Expected autogen CronJob rule of require-labels only, got %+v", cronjob)
+	}
+
+	if findKind(matrix, "DaemonSet", "") != nil {
+		t.Error("Expected only the annotated autogen controllers")
+	}
+}
+
+func Test_KindCoverageAPI(t *testing.T) {
+	handler := api.KindCoverageHandler(newKindPolicyStore(), reportTemplates.KindCoverage)
+
+	t.Run("json with kind filter", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/kind-coverage-reporting?format=json&kinds=ingress", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+
+		matrix := make([]*api.KindCoverage, 0)
+		if err := json.NewDecoder(rr.Body).Decode(&matrix); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(matrix) != 1 || matrix[0].Kind != "Ingress" {
+			t.Errorf("Expected only Ingress, got %+v", matrix)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/kind-coverage-reporting", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+		if !strings.Contains(rr.Body.String(), "Kind: Pod/exec") {
+			t.Error("Expected rendered kind coverage report")
+		}
+	})
+
+	t.Run("namespace authorization", func(t *testing.T) {
+		secured := withUser("dev", api.NamespaceAuthorization(api.NewStaticNamespaceAuthorizer(map[string][]string{"dev": {"other"}}), handler))
+
+		rr := httptest.NewRecorder()
+		secured(rr, httptest.NewRequest("GET", "/kind-coverage-reporting?format=json&kinds=Pod", nil))
+
+		matrix := make([]*api.KindCoverage, 0)
+		if err := json.NewDecoder(rr.Body).Decode(&matrix); err != nil {
+			t.Fatal(err)
+		}
+
+		if pod := findKind(matrix, "Pod", ""); len(matrix) != 2 || pod.Summary.VerifyImages != 0 {
+			t.Errorf("Expected no rules of the team Policy, got %+v", matrix)
+		}
+	})
+}
+
+func Test_KindCoverageMatrixAutogenFallback(t *testing.T) {
+	pod := apiV1.MatchResources{Any: apiV1.ResourceFilters{{ResourceDescription: apiV1.ResourceDescription{Kinds: []string{"Pod"}}}}}
+	mapper := kubernetes.NewMapper()
+
+	t.Run("Empty Status", func(t *testing.T) {
+		policy := mapper.MapPolicy(&apiV1.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "require-labels"},
+			Spec:       apiV1.Spec{Rules: []apiV1.Rule{{Name: "check-team", MatchResources: pod, Validation: apiV1.Validation{Message: "team label required"}}}},
+		}, nil)
+
+		matrix := api.KindCoverageMatrix([]kyverno.Policy{policy})
+
+		for _, controller := range []string{"Deployment", "CronJob", "DaemonSet"} {
+			if coverage := findKind(matrix, controller, ""); coverage == nil || !coverage.Rules[0].Autogen || coverage.Rules[0].Rule != "check-team" {
+				t.Errorf("Expected %s rule derived from the Pod rule, got %+v", controller, coverage)
+			}
+		}
+	})
+
+	t.Run("Autogen Status", func(t *testing.T) {
+		policy := mapper.MapPolicy(&apiV1.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "require-labels"},
+			Spec:       apiV1.Spec{Rules: []apiV1.Rule{{Name: "check-team", MatchResources: pod, Validation: apiV1.Validation{Message: "team label required"}}}},
+			Status: apiV1.PolicyStatus{Autogen: apiV1.AutogenStatus{Rules: []apiV1.Rule{{
+				Name:           "autogen-check-team",
+				MatchResources: apiV1.MatchResources{Any: apiV1.ResourceFilters{{ResourceDescription: apiV1.ResourceDescription{Kinds: []string{"Deployment"}}}}},
+				Validation:     apiV1.Validation{Message: "team label required"},
+			}}}},
+		}, nil)
+
+		matrix := api.KindCoverageMatrix([]kyverno.Policy{policy})
+
+		if deployment := findKind(matrix, "Deployment", ""); deployment == nil || deployment.Rules[0].Rule != "autogen-check-team" {
+			t.Errorf("Expected autogen rule of the status, got %+v", deployment)
+		}
+		if findKind(matrix, "DaemonSet", "") != nil {
+			t.Error("Expected no derived controllers with autogen rules in the status")
+		}
+	})
+}
+
+func Test_KindCoverageMatrixPartialExclusion(t *testing.T) {
+	exclude := &kyverno.MatchResources{Any: []*kyverno.ResourceFilter{
+		{Resources: &kyverno.ResourceDescription{Kinds: []string{"Service"}, Namespaces: []string{"kube-system"}}},
+		{Resources: &kyverno.ResourceDescription{Kinds: []string{"ConfigMap"}}},
+		{Resources: &kyverno.ResourceDescription{Kinds: []string{"Secret"}}, ClusterRoles: []string{"cluster-admin"}},
+	}}
+
+	matrix := api.KindCoverageMatrix([]kyverno.Policy{{Kind: "ClusterPolicy", Name: "require-labels", UID: "1", Rules: []*kyverno.Rule{
+		{Name: "check-team", Type: "validation", Match: resources("Service", "ConfigMap", "Secret"), Exclude: exclude},
+	}}})
+
+	for kind, expected := range map[string][2]bool{"Service": {false, true}, "ConfigMap": {true, false}, "Secret": {false, true}} {
+		rule := findKind(matrix, kind, "").Rules[0]
+		if rule.Excluded != expected[0] || rule.PartiallyExcluded != expected[1] {
+			t.Errorf("Unexpected exclusion of %s: excluded %t, partially excluded %t", kind, rule.Excluded, rule.PartiallyExcluded)
+		}
+	}
+}
+
+func Test_KindCoverageMatrixAllExclusion(t *testing.T) {
+	policy := func(all []*kyverno.ResourceFilter) []kyverno.Policy {
+		return []kyverno.Policy{{Kind: "ClusterPolicy", Name: "require-labels", UID: "1", Rules: []*kyverno.Rule{
+			{Name: "check-team", Type: "validation", Match: resources("Service", "ConfigMap", "Secret"), Exclude: &kyverno.MatchResources{All: all}},
+		}}}
+	}
+
+	t.Run("kinds only", func(t *testing.T) {
+		matrix := api.KindCoverageMatrix(policy([]*kyverno.ResourceFilter{
+			{Resources: &kyverno.ResourceDescription{Kinds: []string{"Service", "ConfigMap"}}},
+			{Resources: &kyverno.ResourceDescription{Kinds: []string{"ConfigMap", "Secret"}}},
+		}))
+
+		for kind, expected := range map[string][2]bool{"Service": {false, false}, "ConfigMap": {true, false}, "Secret": {false, false}} {
+			rule := findKind(matrix, kind, "").Rules[0]
+			if rule.Excluded != expected[0] || rule.PartiallyExcluded != expected[1] {
+				t.Errorf("Unexpected exclusion of %s: excluded %t, partially excluded %t", kind, rule.Excluded, rule.PartiallyExcluded)
+			}
+		}
+	})
+
+	t.Run("further conditions", func(t *testing.T) {
+		matrix := api.KindCoverageMatrix(policy([]*kyverno.ResourceFilter{
+			{Resources: &kyverno.ResourceDescription{Kinds: []string{"Service", "ConfigMap"}}},
+			{Resources: &kyverno.ResourceDescription{Kinds: []string{"ConfigMap", "Secret"}}},
+			{Resources: &kyverno.ResourceDescription{Namespaces: []string{"kube-system"}}},
+		}))
+
+		for kind, expected := range map[string][2]bool{"Service": {false, false}, "ConfigMap": {false, true}, "Secret": {false, false}} {
+			rule := findKind(matrix, kind, "").Rules[0]
+			if rule.Excluded != expected[0] || rule.PartiallyExcluded != expected[1] {
+				t.Errorf("Unexpected exclusion of %s: excluded %t, partially excluded %t", kind, rule.Excluded, rule.PartiallyExcluded)
+			}
+		}
+	})
+}
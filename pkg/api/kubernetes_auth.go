@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"go.uber.org/zap"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authnclient "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	authzclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+type userKey struct{}
+
+// User is the authenticated identity of a request
+type User struct {
+	Name   string
+	UID    string
+	Groups []string
+	Extra  map[string][]string
+}
+
+// WithUser adds the authenticated User to the context
+func WithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFrom returns the authenticated User of the context, if any
+func UserFrom(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(userKey{}).(*User)
+
+	return user, ok
+}
+
+// KubernetesAuth authenticates bearer tokens with TokenReviews and authorizes
+// each request path with SubjectAccessReviews, like the kube-rbac-proxy
+type KubernetesAuth struct {
+	tokens    authnclient.TokenReviewInterface
+	access    authzclient.SubjectAccessReviewInterface
+	audiences []string
+	cache     *gocache.Cache
+}
+
+// Authenticate validates the token and returns the related User
+func (a *KubernetesAuth) Authenticate(ctx context.Context, token string) (*User, error) {
+	hash := sha256.Sum256([]byte(token))
+	cacheKey := "token:" + hex.EncodeToString(hash[:])
+
+	if user, ok := a.cache.Get(cacheKey); ok {
+		return user.(*User), nil
+	}
+
+	review, err := a.tokens.Create(ctx, &authnv1.TokenReview{
+		Spec: authnv1.TokenReviewSpec{Token: token, Audiences: a.audiences},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if !review.Status.Authenticated {
+		return nil, fmt.Errorf("token not authenticated: %s", review.Status.Error)
+	}
+
+	user := &User{
+		Name:   review.Status.User.Username,
+		UID:    review.Status.User.UID,
+		Groups: review.Status.User.Groups,
+		Extra:  make(map[string][]string, len(review.Status.User.Extra)),
+	}
+
+	for key, value := range review.Status.User.Extra {
+		user.Extra[key] = value
+	}
+
+	a.cache.SetDefault(cacheKey, user)
+
+	return user, nil
+}
+
+// Authorize checks if the User is allowed to access the non resource path with the given verb
+func (a *KubernetesAuth) Authorize(ctx context.Context, user *User, path, verb string) (bool, error) {
+	return a.review(ctx, user, authzv1.SubjectAccessReviewSpec{
+		NonResourceAttributes: &authzv1.NonResourceAttributes{Path: path, Verb: verb},
+	})
+}
+
+func (a *KubernetesAuth) review(ctx context.Context, user *User, spec authzv1.SubjectAccessReviewSpec) (bool, error) {
+	spec.User = user.Name
+	spec.UID = user.UID
+	spec.Groups = user.Groups
+	spec.Extra = make(map[string]authzv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		spec.Extra[key] = value
+	}
+
+	cacheKey, err := accessCacheKey(spec)
+	if err != nil {
+		return false, err
+	}
+
+	if allowed, ok := a.cache.Get(cacheKey); ok {
+		return allowed.(bool), nil
+	}
+
+	review, err := a.access.Create(ctx, &authzv1.SubjectAccessReview{Spec: spec}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	a.cache.SetDefault(cacheKey, review.Status.Allowed)
+
+	return review.Status.Allowed, nil
+}
+
+// accessCacheKey hashes the complete user info and attributes of the review,
+// so users with the same name but different groups or extras never share a result
+func accessCacheKey(spec authzv1.SubjectAccessReviewSpec) (string, error) {
+	spec.Groups = append([]string{}, spec.Groups...)
+	sort.Strings(spec.Groups)
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+
+	return "access:" + hex.EncodeToString(hash[:]), nil
+}
+
+// NewKubernetesAuth constructor for KubernetesAuth, review results are cached for the given TTL
+func NewKubernetesAuth(tokens authnclient.TokenReviewInterface, access authzclient.SubjectAccessReviewInterface, audiences []string, ttl time.Duration) *KubernetesAuth {
+	return &KubernetesAuth{
+		tokens:    tokens,
+		access:    access,
+		audiences: audiences,
+		cache:     gocache.New(ttl, 2*ttl),
+	}
+}
+
+// HTTPBearer authenticates and authorizes requests with a Kubernetes bearer token
+func HTTPBearer(auth *KubernetesAuth, next http.HandlerFunc) http.HandlerFunc {
+	if auth == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="restricted"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		user, err := auth.Authenticate(r.Context(), token)
+		if err != nil {
+			zap.L().Debug("failed to authenticate bearer token", zap.Error(err))
+			w.Header().Set("WWW-Authenticate", `Bearer realm="restricted"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		allowed, err := auth.Authorize(r.Context(), user, r.URL.Path, requestVerb(r.Method))
+		if err != nil {
+			zap.L().Error("failed to authorize request", zap.String("user", user.Name), zap.Error(err))
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+	})
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "bearer ") {
+		return "", false
+	}
+
+	token := strings.TrimSpace(header[7:])
+
+	return token, token != ""
+}
+
+// requestVerb maps the HTTP method to the Kubernetes API verb
+func requestVerb(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	default:
+		return "get"
+	}
+}
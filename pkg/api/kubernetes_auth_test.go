@@ -0,0 +1,131 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
+)
+
+func newKubernetesAuth() *api.KubernetesAuth {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authnv1.TokenReview)
+
+		switch review.Spec.Token {
+		case "prometheus-token":
+			review.Status = authnv1.TokenReviewStatus{Authenticated: true, User: authnv1.UserInfo{Username: "system:serviceaccount:monitoring:prometheus"}}
+		case "ui-token":
+			review.Status = authnv1.TokenReviewStatus{Authenticated: true, User: authnv1.UserInfo{Username: "system:serviceaccount:policy-reporter:ui"}}
+		}
+
+		return true, review, nil
+	})
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authzv1.SubjectAccessReview)
+
+		review.Status.Allowed = review.Spec.User == "system:serviceaccount:monitoring:prometheus" && review.Spec.NonResourceAttributes.Path == "/metrics"
+
+		return true, review, nil
+	})
+
+	return api.NewKubernetesAuth(client.AuthenticationV1().TokenReviews(), client.AuthorizationV1().SubjectAccessReviews(), nil, time.Minute)
+}
+
+func Test_HTTPBearer(t *testing.T) {
+	auth := newKubernetesAuth()
+
+	handler := api.HTTPBearer(auth, func(w http.ResponseWriter, r *http.Request) {
+		user, ok := api.UserFrom(r.Context())
+		if !ok {
+			t.Error("Expected authenticated user in request context")
+		} else if user.Name != "system:serviceaccount:monitoring:prometheus" {
+			t.Errorf("Unexpected user: %s", user.Name)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{name: "Authorized", path: "/metrics", token: "prometheus-token", status: http.StatusOK},
+		{name: "Forbidden Path", path: "/policies", token: "prometheus-token", status: http.StatusForbidden},
+		{name: "Forbidden User", path: "/metrics", token: "ui-token", status: http.StatusForbidden},
+		{name: "Invalid Token", path: "/metrics", token: "invalid", status: http.StatusUnauthorized},
+		{name: "Missing Token", path: "/metrics", token: "", status: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", test.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.status {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, test.status)
+			}
+		})
+	}
+}
+
+func Test_KubernetesAuthAccessCacheWithSameUserName(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authnv1.TokenReview)
+
+		switch review.Spec.Token {
+		case "admin-token":
+			review.Status = authnv1.TokenReviewStatus{Authenticated: true, User: authnv1.UserInfo{Username: "oidc:jane", Groups: []string{"admins"}}}
+		case "viewer-token":
+			review.Status = authnv1.TokenReviewStatus{Authenticated: true, User: authnv1.UserInfo{Username: "oidc:jane", Groups: []string{"viewers"}}}
+		}
+
+		return true, review, nil
+	})
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authzv1.SubjectAccessReview)
+
+		review.Status.Allowed = len(review.Spec.Groups) == 1 && review.Spec.Groups[0] == "admins"
+
+		return true, review, nil
+	})
+
+	auth := api.NewKubernetesAuth(client.AuthenticationV1().TokenReviews(), client.AuthorizationV1().SubjectAccessReviews(), nil, time.Minute)
+
+	for token, expected := range map[string]bool{"admin-token": true, "viewer-token": false} {
+		user, err := auth.Authenticate(context.Background(), token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		allowed, err := auth.Authorize(context.Background(), user, "/policies", "get")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if allowed != expected {
+			t.Errorf("Expected %s to be allowed: %v, got %v", token, expected, allowed)
+		}
+	}
+}
@@ -1,5 +1,10 @@
 package api
 
+import (
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/violation"
+)
+
 type Policy struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace,omitempty"`
@@ -7,10 +12,25 @@ type Policy struct {
 }
 
 type VerifyImage struct {
-	Policy       *Policy `json:"policy"`
-	Rule         string  `json:"rule"`
-	Repository   string  `json:"repository"`
-	Image        string  `json:"image"`
-	Key          string  `json:"key"`
-	Attestations string  `json:"attestations,omitempty"`
+	Policy          *Policy                `json:"policy"`
+	Rule            string                 `json:"rule"`
+	Repository      string                 `json:"repository"`
+	Image           string                 `json:"image"`
+	Key             string                 `json:"key"`
+	ImageReferences []string               `json:"imageReferences,omitempty"`
+	Attestors       []*kyverno.AttestorSet `json:"attestors,omitempty"`
+	Attestations    []*kyverno.Attestation `json:"attestations,omitempty"`
+	MutateDigest    bool                   `json:"mutateDigest"`
+	VerifyDigest    bool                   `json:"verifyDigest"`
+	Required        bool                   `json:"required"`
+}
+
+type PolicyEvent struct {
+	Type   string         `json:"type"`
+	Policy kyverno.Policy `json:"policy"`
+}
+
+type ViolationList struct {
+	Items  []violation.PolicyViolation `json:"items"`
+	Counts violation.Counts            `json:"counts"`
 }
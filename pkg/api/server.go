@@ -2,9 +2,9 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
-	"path"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
@@ -12,6 +12,8 @@ import (
 
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/stream"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/violation"
 )
 
 // Server for the optional HTTP REST API
@@ -26,13 +28,96 @@ type Server interface {
 	RegisterMetrics()
 }
 
+// ServerOption configures optional features of the API Server
+type ServerOption = func(*httpServer)
+
+// WithPolicyEvents enables the Policy LifecycleEvent stream API
+func WithPolicyEvents(broker *stream.Broker[kyverno.LifecycleEvent]) ServerOption {
+	return func(s *httpServer) {
+		s.policyEvents = broker
+	}
+}
+
+// WithViolations enables the blocked PolicyViolation stream API
+func WithViolations(broker *stream.Broker[violation.PolicyViolation]) ServerOption {
+	return func(s *httpServer) {
+		s.violations = broker
+	}
+}
+
+// WithViolationStore enables the blocked PolicyViolation REST API
+func WithViolationStore(store *violation.Store) ServerOption {
+	return func(s *httpServer) {
+		s.violationStore = store
+	}
+}
+
+// WithTLS serves the API over HTTPS
+func WithTLS(config *tls.Config) ServerOption {
+	return func(s *httpServer) {
+		s.http.TLSConfig = config
+	}
+}
+
+// WithRequiredClientCertificate requires a verified client certificate for all endpoints except the probes
+func WithRequiredClientCertificate() ServerOption {
+	return func(s *httpServer) {
+		s.requireClientCert = true
+	}
+}
+
+// WithKubernetesAuth enables the authentication and authorization with Kubernetes bearer tokens
+func WithKubernetesAuth(auth *KubernetesAuth) ServerOption {
+	return func(s *httpServer) {
+		s.kubeAuth = auth
+	}
+}
+
+// WithNamespaceAuthorization restricts the namespaced policies and report data to the namespaces the user can access
+func WithNamespaceAuthorization(authorizer NamespaceAuthorizer) ServerOption {
+	return func(s *httpServer) {
+		s.authorizer = authorizer
+	}
+}
+
+// WithReportTemplates replaces the embedded HTML report templates
+func WithReportTemplates(templates *ReportTemplates) ServerOption {
+	return func(s *httpServer) {
+		s.templates = templates
+	}
+}
+
+// WithImageCoverage enables the image signature coverage report
+func WithImageCoverage(generator reporting.ImageGenerator) ServerOption {
+	return func(s *httpServer) {
+		s.images = generator
+	}
+}
+
+// WithNamespaceLookup resolves the namespace labels of the namespace-rules API for requests without labels
+func WithNamespaceLookup(namespaces NamespaceLookup) ServerOption {
+	return func(s *httpServer) {
+		s.namespaces = namespaces
+	}
+}
+
 type httpServer struct {
-	mux     *http.ServeMux
-	store   *kyverno.PolicyStore
-	reports reporting.PolicyReportGenerator
-	http    http.Server
-	synced  func() bool
-	auth    *BasicAuth
+	mux          *http.ServeMux
+	store        *kyverno.PolicyStore
+	reports      reporting.PolicyReportGenerator
+	images       reporting.ImageGenerator
+	templates    *ReportTemplates
+	http         http.Server
+	synced       func() bool
+	auth         *Credentials
+	kubeAuth     *KubernetesAuth
+	authorizer   NamespaceAuthorizer
+	namespaces   NamespaceLookup
+	policyEvents *stream.Broker[kyverno.LifecycleEvent]
+	violations   *stream.Broker[violation.PolicyViolation]
+
+	violationStore    *violation.Store
+	requireClientCert bool
 }
 
 func (s *httpServer) registerHandler() {
@@ -40,35 +125,88 @@ func (s *httpServer) registerHandler() {
 	s.mux.HandleFunc("/ready", ReadyHandler())
 }
 
-func (s *httpServer) middleware(handler http.HandlerFunc) http.HandlerFunc {
-	handler = Gzip(handler)
+func (s *httpServer) middleware(scope string, handler http.HandlerFunc) http.HandlerFunc {
+	return s.authMiddleware(scope, NamespaceAuthorization(s.authorizer, Gzip(handler)))
+}
+
+// streamMiddleware skips the Gzip middleware to flush each event immediately
+func (s *httpServer) streamMiddleware(scope string, handler http.HandlerFunc) http.HandlerFunc {
+	return s.authMiddleware(scope, NamespaceAuthorization(s.authorizer, handler))
+}
 
-	if s.auth != nil {
-		handler = HTTPBasic(s.auth, handler)
+// authMiddleware requires the client certificate if configured and authenticates the request
+func (s *httpServer) authMiddleware(scope string, handler http.HandlerFunc) http.HandlerFunc {
+	if s.requireClientCert {
+		return RequireClientCertificate(s.authentication(scope, handler))
 	}
 
-	return handler
+	return s.authentication(scope, handler)
 }
 
-func (s *httpServer) RegisterMetrics() {
-	handler := promhttp.Handler()
+// authentication uses the Kubernetes authentication for bearer tokens and BasicAuth otherwise.
+// BasicAuth users need access to the scope of the endpoint.
+func (s *httpServer) authentication(scope string, handler http.HandlerFunc) http.HandlerFunc {
+	switch {
+	case s.auth != nil && s.kubeAuth != nil:
+		basic := HTTPBasic(s.auth, scope, handler)
+		bearer := HTTPBearer(s.kubeAuth, handler)
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := bearerToken(r); ok {
+				bearer(w, r)
+				return
+			}
 
-	if s.auth != nil {
-		s.mux.HandleFunc("/metrics", HTTPBasic(s.auth, handler.ServeHTTP))
-		return
+			basic(w, r)
+		}
+	case s.kubeAuth != nil:
+		return HTTPBearer(s.kubeAuth, handler)
+	case s.auth != nil:
+		return HTTPBasic(s.auth, scope, handler)
 	}
 
-	s.mux.Handle("/metrics", handler)
+	return handler
+}
+
+func (s *httpServer) RegisterMetrics() {
+	s.mux.HandleFunc("/metrics", s.authMiddleware(ScopeMetrics, promhttp.Handler().ServeHTTP))
 }
 
 func (s *httpServer) RegisterREST() {
-	s.mux.HandleFunc("/policies", s.middleware(PolicyHandler(s.store)))
-	s.mux.HandleFunc("/verify-image-rules", s.middleware(VerifyImageRulesHandler(s.store)))
-	s.mux.HandleFunc("/namespace-details-reporting", s.middleware(NamespaceReportingHandler(s.reports, path.Join("templates", "reporting"))))
-	s.mux.HandleFunc("/policy-details-reporting", s.middleware(PolicyReportingHandler(s.reports, path.Join("templates", "reporting"))))
+	s.mux.HandleFunc("/policies", s.middleware(ScopePolicies, PolicyHandler(s.store)))
+	s.mux.HandleFunc("/policies/{name}", s.middleware(ScopePolicies, PolicyDetailHandler(s.store)))
+	s.mux.HandleFunc("/policies/{namespace}/{name}", s.middleware(ScopePolicies, PolicyDetailHandler(s.store)))
+	s.mux.HandleFunc("/verify-image-rules", s.middleware(ScopePolicies, VerifyImageRulesHandler(s.store)))
+	s.mux.HandleFunc("/verify-image-rules/match", s.middleware(ScopePolicies, VerifyImageMatchHandler(s.store)))
+	s.mux.HandleFunc("/namespace-rules", s.middleware(ScopePolicies, NamespaceRulesHandler(s.store, s.namespaces)))
+	s.mux.HandleFunc("/namespace-details-reporting", s.middleware(ScopeReporting, NamespaceReportingHandler(s.reports, s.templates.Namespace)))
+	s.mux.HandleFunc("/policy-details-reporting", s.middleware(ScopeReporting, PolicyReportingHandler(s.reports, s.templates.Policy)))
+	s.mux.HandleFunc("/kind-coverage-reporting", s.middleware(ScopeReporting, KindCoverageHandler(s.store, s.templates.KindCoverage)))
+	s.mux.HandleFunc("/admission-risk-reporting", s.middleware(ScopeReporting, AdmissionRiskHandler(s.store, s.templates.AdmissionRisk)))
+
+	if s.images != nil {
+		s.mux.HandleFunc("/image-coverage-reporting", s.middleware(ScopeReporting, ImageCoverageHandler(s.store, s.images, s.templates.ImageCoverage)))
+	}
+
+	if s.policyEvents != nil {
+		s.mux.HandleFunc("/policy-events", s.streamMiddleware(ScopePolicies, PolicyEventStreamHandler(s.policyEvents)))
+	}
+
+	if s.violationStore != nil {
+		s.mux.HandleFunc("/violations", s.middleware(ScopeViolations, ViolationHandler(s.violationStore)))
+	}
+
+	if s.violations != nil {
+		s.mux.HandleFunc("/violation-events", s.streamMiddleware(ScopeViolations, ViolationStreamHandler(s.violations)))
+	}
 }
 
 func (s *httpServer) Start() error {
+	if s.http.TLSConfig != nil {
+		// certificates are provided by the TLSConfig
+		return s.http.ListenAndServeTLS("", "")
+	}
+
 	return s.http.ListenAndServe()
 }
 
@@ -77,7 +215,7 @@ func (s *httpServer) Shutdown(ctx context.Context) error {
 }
 
 // NewServer constructor for a new API Server
-func NewServer(pStore *kyverno.PolicyStore, reports reporting.PolicyReportGenerator, port int, synced func() bool, auth *BasicAuth, logger *zap.Logger) Server {
+func NewServer(pStore *kyverno.PolicyStore, reports reporting.PolicyReportGenerator, port int, synced func() bool, auth *Credentials, logger *zap.Logger, opts ...ServerOption) Server {
 	mux := http.NewServeMux()
 
 	s := &httpServer{
@@ -92,6 +230,19 @@ func NewServer(pStore *kyverno.PolicyStore, reports reporting.PolicyReportGenera
 		},
 	}
 
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.templates == nil {
+		templates, err := NewReportTemplates("")
+		if err != nil {
+			zap.L().Panic("failed to parse embedded report templates", zap.Error(err))
+		}
+
+		s.templates = templates
+	}
+
 	s.registerHandler()
 
 	return s
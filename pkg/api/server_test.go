@@ -2,9 +2,11 @@ package api_test
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
@@ -48,3 +50,61 @@ func Test_NewServer(t *testing.T) {
 
 	<-serviceDone
 }
+
+func Test_NewServerWithRequiredClientCertificate(t *testing.T) {
+	const tlsPort = 9998
+
+	cert, key := generateCertificate(t, "server")
+	clientCert, clientKey := generateCertificate(t, "client")
+
+	reloader, err := api.NewCertificateReloader(func() ([]byte, []byte, error) { return cert, key, nil }, func() ([]byte, error) { return clientCert, nil }, time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected Error: %s", err)
+	}
+
+	server := api.NewServer(kyverno.NewPolicyStore(), &policyReportGeneratorStub{}, tlsPort, func() bool { return true }, nil, logger, api.WithTLS(api.NewTLSConfig(reloader)), api.WithRequiredClientCertificate())
+	server.RegisterREST()
+
+	serviceDone := make(chan struct{})
+	go func() {
+		defer close(serviceDone)
+		server.Start()
+	}()
+	defer func() {
+		server.Shutdown(context.Background())
+		<-serviceDone
+	}()
+
+	certificate, err := tls.X509KeyPair(clientCert, clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	anonymous := http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	client := http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true, Certificates: []tls.Certificate{certificate}}}}
+
+	get := func(client http.Client, path string) int {
+		var res *http.Response
+		for i := 0; i < 50; i++ {
+			if res, err = client.Get(fmt.Sprintf("https://localhost:%d%s", tlsPort, path)); err == nil {
+				res.Body.Close()
+				return res.StatusCode
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		t.Fatalf("Unexpected Error: %s", err)
+		return 0
+	}
+
+	if code := get(anonymous, "/healthz"); code != http.StatusOK {
+		t.Errorf("Expected probes without client certificate, got %d", code)
+	}
+	if code := get(anonymous, "/policies"); code != http.StatusUnauthorized {
+		t.Errorf("Expected required client certificate, got %d", code)
+	}
+	if code := get(client, "/policies"); code != http.StatusOK {
+		t.Errorf("Expected access with client certificate, got %d", code)
+	}
+}
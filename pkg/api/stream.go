@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/stream"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/violation"
+)
+
+// heartbeatInterval keeps idle connections open behind proxies
+var heartbeatInterval = 15 * time.Second
+
+var eventNames = map[kyverno.Event]string{
+	kyverno.Added:   "added",
+	kyverno.Updated: "updated",
+	kyverno.Deleted: "deleted",
+}
+
+// PolicyEventStreamHandler streams Policy LifecycleEvents as Server-Sent Events,
+// events of namespaced Policies are only sent to users with access to the namespace
+func PolicyEventStreamHandler(broker *stream.Broker[kyverno.LifecycleEvent]) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		serverSentEvents(broker, func(event kyverno.LifecycleEvent) (string, any, bool) {
+			if event.Policy.Namespace != "" && !namespaceAllowed(req, event.Policy.Namespace) {
+				return "", nil, false
+			}
+
+			return eventNames[event.Type], PolicyEvent{Type: eventNames[event.Type], Policy: event.Policy}, true
+		})(w, req)
+	}
+}
+
+// ViolationStreamHandler streams blocked PolicyViolations of accessible namespaces as Server-Sent Events
+func ViolationStreamHandler(broker *stream.Broker[violation.PolicyViolation]) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		namespaces := req.URL.Query()["namespaces"]
+		policies := req.URL.Query()["policies"]
+		severities := req.URL.Query()["severities"]
+
+		serverSentEvents(broker, func(pv violation.PolicyViolation) (string, any, bool) {
+			if !namespaceAllowed(req, pv.Resource.Namespace) || !includes(pv.Resource.Namespace, namespaces) || !includes(pv.Policy.Name, policies) || !includes(pv.Policy.Severity, severities) {
+				return "", nil, false
+			}
+
+			return "violation", pv, true
+		})(w, req)
+	}
+}
+
+// serverSentEvents subscribes to the broker and writes each item accepted by the mapper as event.
+// Clients can resume with the Last-Event-ID header or the lastEventId query parameter,
+// a reset event is sent first if events since the last event ID are lost.
+func serverSentEvents[T any](broker *stream.Broker[T], mapper func(T) (string, any, bool)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+
+		lastID := req.Header.Get("Last-Event-ID")
+		if lastID == "" {
+			lastID = req.URL.Query().Get("lastEventId")
+		}
+
+		subscription, err := broker.Subscribe(lastID)
+		if err != nil {
+			http.Error(w, "invalid last event id", http.StatusBadRequest)
+			return
+		}
+		defer subscription.Cancel()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		write := func(message stream.Message[T]) error {
+			name, data, ok := mapper(message.Data)
+			if !ok {
+				return nil
+			}
+
+			content, err := json.Marshal(data)
+			if err != nil {
+				return err
+			}
+
+			if _, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", message.EventID(), name, content); err != nil {
+				return err
+			}
+
+			flusher.Flush()
+
+			return nil
+		}
+
+		if subscription.Reset {
+			// the last received event is from a previous process or no longer kept,
+			// clients have to reload the current state instead of relying on the missed events
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: reset\ndata: {\"message\":\"events since the last event id are lost\"}\n\n", subscription.EventID); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+
+		for _, message := range subscription.Backlog {
+			if err := write(message); err != nil {
+				zap.L().Debug("failed to write event", zap.Error(err))
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-req.Context().Done():
+				return
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			case message, ok := <-subscription.Messages:
+				if !ok {
+					return
+				}
+
+				if err := write(message); err != nil {
+					zap.L().Debug("failed to write event", zap.Error(err))
+					return
+				}
+			}
+		}
+	}
+}
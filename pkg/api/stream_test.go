@@ -0,0 +1,199 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/stream"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/violation"
+)
+
+func Test_PolicyEventStreamAPI(t *testing.T) {
+	broker := stream.NewBroker[kyverno.LifecycleEvent](10)
+	broker.Publish(kyverno.LifecycleEvent{Type: kyverno.Added, Policy: kyverno.Policy{Name: "require-labels"}})
+	broker.Publish(kyverno.LifecycleEvent{Type: kyverno.Deleted, Policy: kyverno.Policy{Name: "require-ressources", Namespace: "test"}})
+	broker.Publish(kyverno.LifecycleEvent{Type: kyverno.Added, Policy: kyverno.Policy{Name: "disallow-latest", Namespace: "other"}})
+	broker.Publish(kyverno.LifecycleEvent{Type: kyverno.Added, Policy: kyverno.Policy{Name: "require-probes"}})
+
+	t.Run("Resume", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, "GET", "/policy-events", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Last-Event-ID", broker.Epoch()+"-1")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.PolicyEventStreamHandler(broker))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		if contentType := rr.Header().Get("Content-Type"); contentType != "text/event-stream" {
+			t.Errorf("handler returned unexpected Content-Type: %s", contentType)
+		}
+
+		expected := "id: " + broker.Epoch() + "-2\nevent: deleted\ndata: {\"type\":\"deleted\",\"policy\":{\"kind\":\"\",\"apiVersion\":\"\",\"name\":\"require-ressources\",\"namespace\":\"test\""
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+		if strings.Contains(rr.Body.String(), "-1\n") {
+			t.Errorf("handler should skip already received events: %v", rr.Body.String())
+		}
+	})
+
+	t.Run("Namespace Authorization", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, "GET", "/policy-events", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Last-Event-ID", broker.Epoch()+"-1")
+
+		rr := httptest.NewRecorder()
+		handler := withUser("dev", api.NamespaceAuthorization(api.NewStaticNamespaceAuthorizer(map[string][]string{"dev": {"other"}}), api.PolicyEventStreamHandler(broker)))
+
+		handler.ServeHTTP(rr, req)
+
+		if !strings.Contains(rr.Body.String(), `"name":"require-probes"`) {
+			t.Errorf("handler should send events of ClusterPolicies: %v", rr.Body.String())
+		}
+		if !strings.Contains(rr.Body.String(), `"namespace":"other"`) {
+			t.Errorf("handler should send events of accessible namespaces: %v", rr.Body.String())
+		}
+		if strings.Contains(rr.Body.String(), `"namespace":"test"`) {
+			t.Errorf("handler should skip events of not accessible namespaces: %v", rr.Body.String())
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, "GET", "/policy-events?lastEventId=1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.PolicyEventStreamHandler(broker))
+
+		handler.ServeHTTP(rr, req)
+
+		expected := "id: " + broker.Epoch() + "-4\nevent: reset\n"
+		if !strings.HasPrefix(rr.Body.String(), expected) {
+			t.Errorf("handler should send a reset event for IDs of another epoch: got %v want %v", rr.Body.String(), expected)
+		}
+		if strings.Contains(rr.Body.String(), "event: added") {
+			t.Errorf("handler should not send events of another epoch: %v", rr.Body.String())
+		}
+	})
+
+	t.Run("Invalid Last Event ID", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/policy-events?lastEventId=abc", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.PolicyEventStreamHandler(broker))
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+		}
+	})
+}
+
+func Test_ViolationStreamAPI(t *testing.T) {
+	broker := stream.NewBroker[violation.PolicyViolation](10)
+	broker.Publish(violation.PolicyViolation{
+		Resource: violation.Resource{Kind: "Pod", Name: "nginx", Namespace: "test"},
+		Policy:   violation.Policy{Name: "require-labels", Rule: "check-labels", Severity: "medium"},
+	})
+	broker.Publish(violation.PolicyViolation{
+		Resource: violation.Resource{Kind: "Pod", Name: "nginx", Namespace: "default"},
+		Policy:   violation.Policy{Name: "require-labels", Rule: "check-labels", Severity: "medium"},
+	})
+	broker.Publish(violation.PolicyViolation{
+		Resource: violation.Resource{Kind: "Deployment", Name: "api", Namespace: "test"},
+		Policy:   violation.Policy{Name: "require-labels", Rule: "autogen-check-labels", Severity: "medium"},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "/violation-events?namespaces=test&severities=medium", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Last-Event-ID", broker.Epoch()+"-1")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(api.ViolationStreamHandler(broker))
+
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	expected := "id: " + broker.Epoch() + "-3\nevent: violation\ndata: {\"resource\":{\"kind\":\"Deployment\",\"name\":\"api\",\"namespace\":\"test\"},\"policy\":{\"name\":\"require-labels\",\"rule\":\"autogen-check-labels\""
+	if !strings.Contains(rr.Body.String(), expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+	}
+	if strings.Contains(rr.Body.String(), `"namespace":"default"`) {
+		t.Errorf("handler should filter violations of other namespaces: %v", rr.Body.String())
+	}
+}
+
+func Test_ViolationStreamAPINamespaceAuthorization(t *testing.T) {
+	broker := stream.NewBroker[violation.PolicyViolation](10)
+	broker.Publish(violation.PolicyViolation{
+		Resource: violation.Resource{Kind: "Pod", Name: "nginx", Namespace: "kube-system"},
+		Policy:   violation.Policy{Name: "require-labels", Rule: "check-labels", Severity: "medium"},
+	})
+	broker.Publish(violation.PolicyViolation{
+		Resource: violation.Resource{Kind: "Pod", Name: "nginx", Namespace: "test"},
+		Policy:   violation.Policy{Name: "require-labels", Rule: "check-labels", Severity: "medium"},
+	})
+	broker.Publish(violation.PolicyViolation{
+		Resource: violation.Resource{Kind: "Pod", Name: "nginx", Namespace: "default"},
+		Policy:   violation.Policy{Name: "require-labels", Rule: "check-labels", Severity: "medium"},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "/violation-events?namespaces=test&namespaces=default", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Last-Event-ID", broker.Epoch()+"-1")
+
+	rr := httptest.NewRecorder()
+	handler := withUser("dev", api.NamespaceAuthorization(api.NewStaticNamespaceAuthorizer(map[string][]string{"dev": {"default"}}), api.ViolationStreamHandler(broker)))
+
+	handler.ServeHTTP(rr, req)
+
+	if !strings.Contains(rr.Body.String(), `"namespace":"default"`) {
+		t.Errorf("handler should send violations of accessible namespaces: %v", rr.Body.String())
+	}
+	if strings.Contains(rr.Body.String(), `"namespace":"test"`) {
+		t.Errorf("handler should skip violations of not accessible namespaces: %v", rr.Body.String())
+	}
+}
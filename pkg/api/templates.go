@@ -0,0 +1,137 @@
+package api
+
+import (
+	"errors"
+	"html/template"
+	"io/fs"
+	"os"
+	"sort"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/templates"
+)
+
+const (
+	policyReportTemplate    = "policy-report-details.html"
+	namespaceReportTemplate = "namespace-report-details.html"
+	imageCoverageTemplate   = "image-coverage.html"
+	kindCoverageTemplate    = "kind-coverage.html"
+	admissionRiskTemplate   = "admission-risk.html"
+)
+
+// ReportTemplates are the parsed HTML report templates
+type ReportTemplates struct {
+	Policy        *template.Template
+	Namespace     *template.Template
+	ImageCoverage *template.Template
+	KindCoverage  *template.Template
+	AdmissionRisk *template.Template
+}
+
+// NewReportTemplates parses the embedded report templates once. Files of the optional override
+// directory replace the embedded files with the same name, additional files can be used as partials.
+func NewReportTemplates(overrideDir string) (*ReportTemplates, error) {
+	base, err := fs.Sub(templates.FS, "reporting")
+	if err != nil {
+		return nil, err
+	}
+
+	source := fs.FS(base)
+	if overrideDir != "" {
+		source = overlayFS{override: os.DirFS(overrideDir), base: base}
+	}
+
+	files, err := templateFiles(source)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := template.New(policyReportTemplate).Funcs(funcMap).ParseFS(source, files...)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, err := template.New(namespaceReportTemplate).Funcs(funcMap).ParseFS(source, files...)
+	if err != nil {
+		return nil, err
+	}
+
+	imageCoverage, err := template.New(imageCoverageTemplate).Funcs(funcMap).ParseFS(source, files...)
+	if err != nil {
+		return nil, err
+	}
+
+	kindCoverage, err := template.New(kindCoverageTemplate).Funcs(funcMap).ParseFS(source, files...)
+	if err != nil {
+		return nil, err
+	}
+
+	admissionRisk, err := template.New(admissionRiskTemplate).Funcs(funcMap).ParseFS(source, files...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReportTemplates{Policy: policy, Namespace: namespace, ImageCoverage: imageCoverage, KindCoverage: kindCoverage, AdmissionRisk: admissionRisk}, nil
+}
+
+// templateFiles lists all files of the template root directory
+func templateFiles(source fs.FS) ([]string, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, entry.Name())
+		}
+	}
+
+	return files, nil
+}
+
+// overlayFS prefers the files of the override filesystem
+type overlayFS struct {
+	override fs.FS
+	base     fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	file, err := o.override.Open(name)
+	if err == nil {
+		return file, nil
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return o.base.Open(name)
+}
+
+// ReadDir merges the entries of both filesystems
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(o.base, name)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := fs.ReadDir(o.override, name)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]fs.DirEntry, len(entries)+len(overrides))
+	for _, entry := range append(entries, overrides...) {
+		merged[entry.Name()] = entry
+	}
+
+	list := make([]fs.DirEntry, 0, len(merged))
+	for _, entry := range merged {
+		list = append(list, entry)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+
+	return list, nil
+}
@@ -0,0 +1,57 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
+)
+
+var reportTemplates = mustReportTemplates("")
+
+func mustReportTemplates(overrideDir string) *api.ReportTemplates {
+	templates, err := api.NewReportTemplates(overrideDir)
+	if err != nil {
+		panic(err)
+	}
+
+	return templates
+}
+
+func Test_ReportTemplateOverrides(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "mui.css"), []byte(".brand { color: #326ce5; }"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	templates := mustReportTemplates(dir)
+
+	req := httptest.NewRequest("GET", "/policy-details-reporting", nil)
+	rr := httptest.NewRecorder()
+
+	api.PolicyReportingHandler(&policyReportGeneratorStub{}, templates.Policy)(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+
+	body := rr.Body.String()
+	if !strings.Contains(body, ".brand { color: #326ce5; }") {
+		t.Error("Expected overridden mui.css in the rendered report")
+	}
+
+	if !strings.Contains(body, "Disallow Capabilities") {
+		t.Error("Expected the embedded report template to render the policy")
+	}
+}
+
+func Test_ReportTemplateInvalidOverrideDir(t *testing.T) {
+	if _, err := api.NewReportTemplates(filepath.Join(t.TempDir(), "not-exist")); err == nil {
+		t.Error("Expected error for a not existing override directory")
+	}
+}
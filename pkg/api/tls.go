@@ -0,0 +1,212 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CertificateSource loads the PEM encoded certificate and key
+type CertificateSource = func() (cert []byte, key []byte, err error)
+
+// ClientCASource loads the PEM encoded client CA bundle
+type ClientCASource = func() ([]byte, error)
+
+// FileCertificateSource reads the certificate and key from the filesystem
+func FileCertificateSource(certFile, keyFile string) CertificateSource {
+	return func() ([]byte, []byte, error) {
+		cert, err := os.ReadFile(certFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return cert, key, nil
+	}
+}
+
+// FileClientCASource reads the client CA bundle from the filesystem
+func FileClientCASource(file string) ClientCASource {
+	return func() ([]byte, error) {
+		return os.ReadFile(file)
+	}
+}
+
+// CertificateReloader serves the latest certificate and client CA pool of its sources.
+// The sources are reloaded in the background, TLS handshakes never wait for a reload.
+type CertificateReloader struct {
+	source      CertificateSource
+	clientCA    ClientCASource
+	interval    time.Duration
+	lock        *sync.Mutex
+	rawCert     []byte
+	rawKey      []byte
+	rawCA       []byte
+	certificate atomic.Pointer[tls.Certificate]
+	pool        atomic.Pointer[x509.CertPool]
+}
+
+// GetCertificate implements the tls.Config.GetCertificate callback
+func (r *CertificateReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.certificate.Load(), nil
+}
+
+// ClientCAs returns the current client CA pool, nil without client CA source
+func (r *CertificateReloader) ClientCAs() *x509.CertPool {
+	return r.pool.Load()
+}
+
+// Run reloads the sources once per interval until the context is done
+func (r *CertificateReloader) Run(ctx context.Context) {
+	if r.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Reload(); err != nil {
+				zap.L().Error("failed to reload TLS certificate, keep serving the current one", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Reload checks the sources for changes, invalid changes keep the current certificate and client CA pool
+func (r *CertificateReloader) Reload() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.reloadCertificate(); err != nil {
+		return err
+	}
+
+	return r.reloadClientCA()
+}
+
+func (r *CertificateReloader) reloadCertificate() error {
+	cert, key, err := r.source()
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(cert, r.rawCert) && bytes.Equal(key, r.rawKey) {
+		return nil
+	}
+
+	certificate, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return err
+	}
+
+	if r.certificate.Load() != nil {
+		zap.L().Info("TLS certificate reloaded")
+	}
+
+	r.rawCert = cert
+	r.rawKey = key
+	r.certificate.Store(&certificate)
+
+	return nil
+}
+
+func (r *CertificateReloader) reloadClientCA() error {
+	if r.clientCA == nil {
+		return nil
+	}
+
+	ca, err := r.clientCA()
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(ca, r.rawCA) {
+		return nil
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return fmt.Errorf("no valid client CA certificate found")
+	}
+
+	if r.pool.Load() != nil {
+		zap.L().Info("TLS client CA reloaded")
+	}
+
+	r.rawCA = ca
+	r.pool.Store(pool)
+
+	return nil
+}
+
+// NewCertificateReloader constructor for CertificateReloader, it fails if the initial certificate
+// or client CA can not be loaded. The client CA source is optional.
+func NewCertificateReloader(source CertificateSource, clientCA ClientCASource, interval time.Duration) (*CertificateReloader, error) {
+	r := &CertificateReloader{
+		source:   source,
+		clientCA: clientCA,
+		interval: interval,
+		lock:     &sync.Mutex{},
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// NewTLSConfig creates the server TLS configuration. With a client CA source, presented client certificates
+// are verified against the current client CA pool. Required client certificates are enforced per endpoint
+// by RequireClientCertificate, so the probes of the kubelet keep working with mTLS.
+func NewTLSConfig(reloader *CertificateReloader) *tls.Config {
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if reloader.ClientCAs() == nil {
+		return config
+	}
+
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+	config.GetConfigForClient = func(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+		current := config.Clone()
+		current.GetConfigForClient = nil
+		current.ClientCAs = reloader.ClientCAs()
+
+		return current, nil
+	}
+
+	return config
+}
+
+// RequireClientCertificate rejects requests without verified client certificate
+func RequireClientCertificate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,238 @@
+package api_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/api"
+)
+
+func generateCertificate(t *testing.T, name string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func commonName(t *testing.T, certificate *tls.Certificate) string {
+	cert, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert.Subject.CommonName
+}
+
+func Test_CertificateReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := path.Join(dir, "tls.crt")
+	keyFile := path.Join(dir, "tls.key")
+
+	cert, key := generateCertificate(t, "initial")
+	os.WriteFile(certFile, cert, 0o600)
+	os.WriteFile(keyFile, key, 0o600)
+
+	reloader, err := api.NewCertificateReloader(api.FileCertificateSource(certFile, keyFile), nil, 0)
+	if err != nil {
+		t.Fatalf("Unexpected Error: %s", err)
+	}
+
+	certificate, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("Unexpected Error: %s", err)
+	}
+	if name := commonName(t, certificate); name != "initial" {
+		t.Errorf("Expected initial certificate, got %s", name)
+	}
+
+	t.Run("Rotation", func(t *testing.T) {
+		cert, key := generateCertificate(t, "rotated")
+		os.WriteFile(certFile, cert, 0o600)
+		os.WriteFile(keyFile, key, 0o600)
+
+		if err := reloader.Reload(); err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		certificate, _ := reloader.GetCertificate(nil)
+		if name := commonName(t, certificate); name != "rotated" {
+			t.Errorf("Expected rotated certificate, got %s", name)
+		}
+	})
+
+	t.Run("Keep current certificate on invalid rotation", func(t *testing.T) {
+		os.WriteFile(keyFile, []byte("invalid"), 0o600)
+
+		if err := reloader.Reload(); err == nil {
+			t.Error("Expected error for invalid key")
+		}
+
+		certificate, _ := reloader.GetCertificate(nil)
+		if name := commonName(t, certificate); name != "rotated" {
+			t.Errorf("Expected rotated certificate, got %s", name)
+		}
+	})
+
+	t.Run("Missing Certificate", func(t *testing.T) {
+		_, err := api.NewCertificateReloader(api.FileCertificateSource(path.Join(dir, "missing.crt"), keyFile), nil, 0)
+		if err == nil {
+			t.Error("Expected error for missing certificate")
+		}
+	})
+}
+
+func Test_CertificateReloaderRun(t *testing.T) {
+	names := make(chan string, 1)
+	names <- "initial"
+
+	var cert, key []byte
+
+	reloader, err := api.NewCertificateReloader(func() ([]byte, []byte, error) {
+		select {
+		case name := <-names:
+			cert, key = generateCertificate(t, name)
+		default:
+		}
+
+		return cert, key, nil
+	}, nil, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected Error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go reloader.Run(ctx)
+
+	names <- "rotated"
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		certificate, _ := reloader.GetCertificate(nil)
+		if commonName(t, certificate) == "rotated" {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Error("Expected rotated certificate to be loaded in the background")
+}
+
+func Test_NewTLSConfig(t *testing.T) {
+	cert, key := generateCertificate(t, "server")
+	source := func() ([]byte, []byte, error) { return cert, key, nil }
+
+	t.Run("Without Client CA", func(t *testing.T) {
+		reloader, err := api.NewCertificateReloader(source, nil, time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		config := api.NewTLSConfig(reloader)
+		if config.ClientAuth != tls.NoClientCert || config.GetConfigForClient != nil {
+			t.Errorf("Expected no client certificate verification")
+		}
+	})
+
+	t.Run("Client CA Reload", func(t *testing.T) {
+		ca, _ := generateCertificate(t, "client-ca")
+		clientCA := func() ([]byte, error) { return ca, nil }
+
+		reloader, err := api.NewCertificateReloader(source, clientCA, time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		config := api.NewTLSConfig(reloader)
+		if config.ClientAuth != tls.VerifyClientCertIfGiven {
+			t.Errorf("Expected client certificate verification on the TLS level")
+		}
+
+		initial, err := config.GetConfigForClient(nil)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		ca, _ = generateCertificate(t, "rotated-ca")
+		if err := reloader.Reload(); err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		rotated, _ := config.GetConfigForClient(nil)
+		if rotated.ClientCAs == nil || rotated.ClientCAs.Equal(initial.ClientCAs) {
+			t.Error("Expected reloaded client CA pool")
+		}
+	})
+
+	t.Run("Invalid Client CA", func(t *testing.T) {
+		if _, err := api.NewCertificateReloader(source, func() ([]byte, error) { return []byte("invalid"), nil }, time.Minute); err == nil {
+			t.Error("Expected error for invalid client CA")
+		}
+	})
+}
+
+func Test_RequireClientCertificate(t *testing.T) {
+	handler := api.RequireClientCertificate(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("Without Certificate", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/policies", nil)
+		req.TLS = &tls.ConnectionState{}
+
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("Verified Certificate", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/policies", nil)
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+	})
+}
@@ -1,17 +1,68 @@
 package config
 
-// BasicAuth configuration
+// BasicAuthUser configuration
+type BasicAuthUser struct {
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+	Scopes   []string `mapstructure:"scopes"`
+}
+
+// BasicAuth configuration. Users of the SecretRef are reloaded every ReloadInterval seconds,
+// the HtpasswdFile is only read on startup.
 type BasicAuth struct {
-	Username  string `mapstructure:"username"`
-	Password  string `mapstructure:"password"`
-	SecretRef string `mapstructure:"secretRef"`
+	Username       string          `mapstructure:"username"`
+	Password       string          `mapstructure:"password"`
+	Scopes         []string        `mapstructure:"scopes"`
+	Users          []BasicAuthUser `mapstructure:"users"`
+	HtpasswdFile   string          `mapstructure:"htpasswdFile"`
+	SecretRef      string          `mapstructure:"secretRef"`
+	ReloadInterval int             `mapstructure:"reloadInterval"`
+}
+
+// KubernetesAuth configuration
+type KubernetesAuth struct {
+	Enabled   bool     `mapstructure:"enabled"`
+	Audiences []string `mapstructure:"audiences"`
+	CacheTTL  int      `mapstructure:"cacheTTL"`
+}
+
+// TLS configuration
+type TLS struct {
+	CertFile          string `mapstructure:"certFile"`
+	KeyFile           string `mapstructure:"keyFile"`
+	SecretRef         string `mapstructure:"secretRef"`
+	ClientCAFile      string `mapstructure:"clientCAFile"`
+	RequireClientCert bool   `mapstructure:"requireClientCert"`
+	ReloadInterval    int    `mapstructure:"reloadInterval"`
+}
+
+// Enabled if a certificate source is configured
+func (t TLS) Enabled() bool {
+	return t.SecretRef != "" || (t.CertFile != "" && t.KeyFile != "")
+}
+
+// NamespaceMapping configures the accessible namespaces of a user
+type NamespaceMapping struct {
+	User       string   `mapstructure:"user"`
+	Namespaces []string `mapstructure:"namespaces"`
+}
+
+// NamespaceAuthorization configuration
+type NamespaceAuthorization struct {
+	// Mode is "subjectAccessReview" or "static", the authorization is disabled if empty
+	Mode     string             `mapstructure:"mode"`
+	Mappings []NamespaceMapping `mapstructure:"mappings"`
 }
 
 // API configuration
 type API struct {
-	Port      int       `mapstructure:"port"`
-	Logging   bool      `mapstructure:"logging"`
-	BasicAuth BasicAuth `mapstructure:"basicAuth"`
+	Port           int            `mapstructure:"port"`
+	Logging        bool           `mapstructure:"logging"`
+	BasicAuth      BasicAuth      `mapstructure:"basicAuth"`
+	KubernetesAuth KubernetesAuth `mapstructure:"kubernetesAuth"`
+	TLS            TLS            `mapstructure:"tls"`
+
+	NamespaceAuthorization NamespaceAuthorization `mapstructure:"namespaceAuthorization"`
 }
 
 type Logging struct {
@@ -22,7 +73,11 @@ type Logging struct {
 
 // REST configuration
 type REST struct {
-	Enabled bool `mapstructure:"enabled"`
+	Enabled      bool   `mapstructure:"enabled"`
+	EventHistory int    `mapstructure:"eventHistory"`
+	TemplateDir  string `mapstructure:"templateDir"`
+	// ImageCoverage enables the image signature coverage report, requires list access to Pods and workloads
+	ImageCoverage bool `mapstructure:"imageCoverage"`
 }
 
 // Metrics configuration
@@ -53,6 +108,7 @@ type BlockReports struct {
 	Results        Results `mapstructure:"results"`
 	Source         string  `mapstructure:"source"`
 	EventNamespace string  `mapstructure:"eventNamespace"`
+	History        int     `mapstructure:"history"`
 }
 
 // Config of the Policyer
@@ -2,6 +2,11 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -24,6 +29,7 @@ import (
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting"
 	rk8s "github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting/kubernetes"
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/secrets"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/stream"
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/violation"
 	vk8s "github.com/kyverno/policy-reporter-kyverno-plugin/pkg/violation/kubernetes"
 )
@@ -37,9 +43,16 @@ type Resolver struct {
 	leaderClient *leaderelection.Client
 	policyStore  *kyverno.PolicyStore
 	policyClient kyverno.PolicyClient
+	nsClient     k8s.NamespaceClient
 	eventClient  violation.EventClient
 	polrClient   policyreport.Client
 	publisher    *kyverno.EventPublisher
+	policyEvents *stream.Broker[kyverno.LifecycleEvent]
+	violations   *stream.Broker[violation.PolicyViolation]
+	vStore       *violation.Store
+	kubeAuth     *api.KubernetesAuth
+	credentials  *api.Credentials
+	authSecret   secrets.Values
 	vPulisher    *violation.Publisher
 	logger       *zap.Logger
 }
@@ -56,25 +69,87 @@ func (r *Resolver) SecretClient() (secrets.Client, error) {
 }
 
 // APIServer resolver method
-func (r *Resolver) APIServer(ctx context.Context, synced func() bool) api.Server {
+func (r *Resolver) APIServer(ctx context.Context, synced func() bool) (api.Server, error) {
 	var logger *zap.Logger
 	if r.config.API.Logging {
 		logger, _ = r.Logger()
 	}
 
-	authConfig := &r.config.API.BasicAuth
-	if authConfig.SecretRef != "" {
-		r.loadSecretRef(ctx, authConfig)
+	auth, err := r.BasicAuthCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if auth != nil {
+		zap.L().Info("API BasicAuth enabled", zap.Int("users", auth.Len()))
+	}
+
+	templates, err := api.NewReportTemplates(r.config.REST.TemplateDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report templates: %w", err)
+	}
+
+	opts := []api.ServerOption{api.WithPolicyEvents(r.PolicyEventBroker()), api.WithReportTemplates(templates)}
+	if r.config.BlockReports.Enabled {
+		opts = append(opts, api.WithViolations(r.ViolationBroker()), api.WithViolationStore(r.ViolationStore()))
+	}
+
+	if r.config.REST.Enabled {
+		namespaces, err := r.NamespaceClient()
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, api.WithNamespaceLookup(namespaces))
 	}
 
-	var auth *api.BasicAuth
-	if authConfig.Username != "" && authConfig.Password != "" {
-		auth = &api.BasicAuth{
-			Username: authConfig.Username,
-			Password: authConfig.Password,
+	if r.config.REST.ImageCoverage {
+		images, err := r.ImageGenerator()
+		if err != nil {
+			return nil, err
 		}
 
-		zap.L().Info("API BasicAuth enabled")
+		opts = append(opts, api.WithImageCoverage(images))
+	}
+
+	if r.config.API.KubernetesAuth.Enabled {
+		kubeAuth, err := r.KubernetesAuth()
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, api.WithKubernetesAuth(kubeAuth))
+
+		zap.L().Info("API Kubernetes TokenReview authentication enabled")
+	}
+
+	if r.config.API.NamespaceAuthorization.Mode != "" {
+		authorizer, err := r.NamespaceAuthorizer()
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, api.WithNamespaceAuthorization(authorizer))
+
+		zap.L().Info("API namespace authorization enabled", zap.String("mode", r.config.API.NamespaceAuthorization.Mode))
+	}
+
+	if r.config.API.TLS.RequireClientCert && (r.config.API.TLS.ClientCAFile == "" || !r.config.API.TLS.Enabled()) {
+		return nil, fmt.Errorf("api.tls.requireClientCert requires TLS with api.tls.clientCAFile")
+	}
+
+	if r.config.API.TLS.Enabled() {
+		tlsConfig, err := r.TLSConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, api.WithTLS(tlsConfig))
+		if r.config.API.TLS.RequireClientCert {
+			opts = append(opts, api.WithRequiredClientCertificate())
+		}
+
+		zap.L().Info("API TLS enabled", zap.Bool("clientCertificates", r.config.API.TLS.ClientCAFile != ""))
 	}
 
 	return api.NewServer(
@@ -84,7 +159,210 @@ func (r *Resolver) APIServer(ctx context.Context, synced func() bool) api.Server
 		synced,
 		auth,
 		logger,
+		opts...,
+	), nil
+}
+
+// BasicAuthCredentials resolver method, returns nil without any configured user.
+// With a secretRef the credentials are always enabled, the users can change with the secret.
+func (r *Resolver) BasicAuthCredentials(ctx context.Context) (*api.Credentials, error) {
+	if r.credentials != nil {
+		return r.credentials, nil
+	}
+
+	authConfig := r.config.API.BasicAuth
+	if authConfig.SecretRef != "" {
+		r.authSecret = r.loadSecretRef(ctx, authConfig.SecretRef)
+	}
+
+	users, err := r.basicAuthUsers(r.authSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := api.NewCredentials(users...)
+	if credentials.Len() == 0 && authConfig.SecretRef == "" {
+		return nil, nil
+	}
+
+	r.credentials = credentials
+
+	return r.credentials, nil
+}
+
+// BasicAuthWatcher resolver method, updates the BasicAuthCredentials on each change of the basic auth secret.
+// The htpasswdFile is only read on startup.
+func (r *Resolver) BasicAuthWatcher(ctx context.Context) (secrets.Watcher, error) {
+	client, err := r.SecretClient()
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := r.BasicAuthCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &sync.Mutex{}
+	secretRef := r.config.API.BasicAuth.SecretRef
+
+	interval := time.Duration(r.config.API.BasicAuth.ReloadInterval) * time.Second
+
+	return secrets.NewWatcher(client, secretRef, interval, func(values secrets.Values) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if values == r.authSecret {
+			return
+		}
+
+		users, err := r.basicAuthUsers(values)
+		if err != nil {
+			zap.L().Error("failed to update basic auth credentials, keep the current ones", zap.String("secret", secretRef), zap.Error(err))
+			return
+		}
+
+		r.authSecret = values
+		credentials.Update(users...)
+
+		zap.L().Info("basic auth credentials updated", zap.String("secret", secretRef), zap.Int("users", credentials.Len()))
+	}), nil
+}
+
+// basicAuthUsers merges the users of the basic auth secret values with the configured users,
+// the secret username and password take precedence over the configured ones
+func (r *Resolver) basicAuthUsers(values secrets.Values) ([]api.BasicAuth, error) {
+	authConfig := r.config.API.BasicAuth
+
+	users := make([]api.BasicAuth, 0)
+	if values.Htpasswd != "" {
+		secretUsers, err := api.ParseHtpasswd(strings.NewReader(values.Htpasswd))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse htpasswd of basic auth secret: %w", err)
+		}
+
+		users = append(users, secretUsers...)
+	}
+
+	if authConfig.HtpasswdFile != "" {
+		file, err := os.Open(authConfig.HtpasswdFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read htpasswd file: %w", err)
+		}
+		defer file.Close()
+
+		fileUsers, err := api.ParseHtpasswd(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse htpasswd file: %w", err)
+		}
+
+		users = append(users, fileUsers...)
+	}
+
+	if values.Username != "" {
+		authConfig.Username = values.Username
+	}
+	if values.Password != "" {
+		authConfig.Password = values.Password
+	}
+
+	configUsers := append([]BasicAuthUser{{
+		Username: authConfig.Username,
+		Password: authConfig.Password,
+		Scopes:   authConfig.Scopes,
+	}}, authConfig.Users...)
+
+	for _, user := range configUsers {
+		scopes, err := api.ParseScopes(user.Scopes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scopes for basic auth user %s: %w", user.Username, err)
+		}
+
+		users = append(users, api.BasicAuth{Username: user.Username, Password: user.Password, Scopes: scopes})
+	}
+
+	return users, nil
+}
+
+// KubernetesAuth resolver method
+func (r *Resolver) KubernetesAuth() (*api.KubernetesAuth, error) {
+	if r.kubeAuth != nil {
+		return r.kubeAuth, nil
+	}
+
+	clientset, err := r.Clientset()
+	if err != nil {
+		return nil, err
+	}
+
+	r.kubeAuth = api.NewKubernetesAuth(
+		clientset.AuthenticationV1().TokenReviews(),
+		clientset.AuthorizationV1().SubjectAccessReviews(),
+		r.config.API.KubernetesAuth.Audiences,
+		time.Duration(r.config.API.KubernetesAuth.CacheTTL)*time.Second,
 	)
+
+	return r.kubeAuth, nil
+}
+
+// NamespaceAuthorizer resolver method
+func (r *Resolver) NamespaceAuthorizer() (api.NamespaceAuthorizer, error) {
+	config := r.config.API.NamespaceAuthorization
+
+	switch config.Mode {
+	case "subjectAccessReview":
+		kubeAuth, err := r.KubernetesAuth()
+		if err != nil {
+			return nil, err
+		}
+
+		return api.NewSubjectAccessNamespaceAuthorizer(kubeAuth), nil
+	case "static":
+		namespaces := make(map[string][]string, len(config.Mappings))
+		for _, mapping := range config.Mappings {
+			namespaces[mapping.User] = append(namespaces[mapping.User], mapping.Namespaces...)
+		}
+
+		return api.NewStaticNamespaceAuthorizer(namespaces), nil
+	}
+
+	return nil, fmt.Errorf("unknown namespace authorization mode: %s", config.Mode)
+}
+
+// TLSConfig resolver method
+func (r *Resolver) TLSConfig(ctx context.Context) (*tls.Config, error) {
+	config := r.config.API.TLS
+
+	source := api.FileCertificateSource(config.CertFile, config.KeyFile)
+	if config.SecretRef != "" {
+		client, err := r.SecretClient()
+		if err != nil {
+			return nil, err
+		}
+
+		source = func() ([]byte, []byte, error) {
+			values, err := client.Get(ctx, config.SecretRef)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return []byte(values.Certificate), []byte(values.Key), nil
+		}
+	}
+
+	var clientCA api.ClientCASource
+	if config.ClientCAFile != "" {
+		clientCA = api.FileClientCASource(config.ClientCAFile)
+	}
+
+	reloader, err := api.NewCertificateReloader(source, clientCA, time.Duration(config.ReloadInterval)*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate or client CA: %w", err)
+	}
+
+	go reloader.Run(ctx)
+
+	return api.NewTLSConfig(reloader), nil
 }
 
 func (r *Resolver) CRDMetadataClient() (metadata.Interface, error) {
@@ -107,6 +385,22 @@ func (r *Resolver) PolicyStore() *kyverno.PolicyStore {
 	return r.policyStore
 }
 
+// NamespaceClient resolver method
+func (r *Resolver) NamespaceClient() (k8s.NamespaceClient, error) {
+	if r.nsClient != nil {
+		return r.nsClient, nil
+	}
+
+	client, err := r.CRDMetadataClient()
+	if err != nil {
+		return nil, err
+	}
+
+	r.nsClient = k8s.NewNamespaceClient(client)
+
+	return r.nsClient, nil
+}
+
 // EventPublisher resolver method
 func (r *Resolver) EventPublisher() *kyverno.EventPublisher {
 	if r.publisher != nil {
@@ -118,6 +412,39 @@ func (r *Resolver) EventPublisher() *kyverno.EventPublisher {
 	return r.publisher
 }
 
+// PolicyEventBroker resolver method
+func (r *Resolver) PolicyEventBroker() *stream.Broker[kyverno.LifecycleEvent] {
+	if r.policyEvents != nil {
+		return r.policyEvents
+	}
+
+	r.policyEvents = stream.NewBroker[kyverno.LifecycleEvent](r.config.REST.EventHistory)
+
+	return r.policyEvents
+}
+
+// ViolationBroker resolver method
+func (r *Resolver) ViolationBroker() *stream.Broker[violation.PolicyViolation] {
+	if r.violations != nil {
+		return r.violations
+	}
+
+	r.violations = stream.NewBroker[violation.PolicyViolation](r.config.REST.EventHistory)
+
+	return r.violations
+}
+
+// ViolationStore resolver method
+func (r *Resolver) ViolationStore() *violation.Store {
+	if r.vStore != nil {
+		return r.vStore
+	}
+
+	r.vStore = violation.NewStore(r.config.BlockReports.History)
+
+	return r.vStore
+}
+
 // EventPublisher resolver method
 func (r *Resolver) ViolationPublisher() *violation.Publisher {
 	if r.vPulisher != nil {
@@ -200,6 +527,16 @@ func (r *Resolver) Reporting() reporting.PolicyReportGenerator {
 	)
 }
 
+// ImageGenerator resolver method
+func (r *Resolver) ImageGenerator() (reporting.ImageGenerator, error) {
+	clientset, err := r.Clientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return reporting.NewImageGenerator(rk8s.NewWorkloadClient(clientset)), nil
+}
+
 // LeaderElectionClient resolver method
 func (r *Resolver) LeaderElectionClient() (*leaderelection.Client, error) {
 	if r.leaderClient != nil {
@@ -329,27 +666,46 @@ func (r *Resolver) RegisterStoreListener() {
 	r.EventPublisher().RegisterListener(listener.NewStoreListener(r.PolicyStore()))
 }
 
+// RegisterStreamListener resolver method
+func (r *Resolver) RegisterStreamListener() {
+	r.EventPublisher().RegisterListener(listener.NewStreamListener(r.PolicyEventBroker()))
+}
+
+// RegisterViolationStreamListener resolver method
+func (r *Resolver) RegisterViolationStreamListener() {
+	r.ViolationPublisher().RegisterListener(r.ViolationBroker().Publish)
+}
+
+// RegisterViolationStoreListener resolver method
+func (r *Resolver) RegisterViolationStoreListener() {
+	r.ViolationPublisher().RegisterListener(r.ViolationStore().Add)
+}
+
 // RegisterMetricsListener resolver method
-func (r *Resolver) RegisterMetricsListener() {
+func (r *Resolver) RegisterMetricsListener() error {
+	namespaces, err := r.NamespaceClient()
+	if err != nil {
+		return err
+	}
+
 	r.EventPublisher().RegisterListener(listener.NewPolicyMetricsListener())
+	r.EventPublisher().RegisterListener(listener.NewEffectiveActionMetricsListener(namespaces))
+	r.EventPublisher().RegisterListener(listener.NewPolicyReadyMetricsListener())
+
+	return nil
 }
 
-func (r *Resolver) loadSecretRef(ctx context.Context, auth *BasicAuth) {
+func (r *Resolver) loadSecretRef(ctx context.Context, secretRef string) secrets.Values {
 	client, err := r.SecretClient()
 	if err != nil {
-		return
+		return secrets.Values{}
 	}
-	values, err := client.Get(ctx, auth.SecretRef)
+	values, err := client.Get(ctx, secretRef)
 	if err != nil {
 		zap.L().Error("failed to load basic auth secret", zap.Error(err))
 	}
 
-	if values.Username != "" {
-		auth.Username = values.Username
-	}
-	if values.Password != "" {
-		auth.Password = values.Password
-	}
+	return values
 }
 
 // NewResolver constructor function
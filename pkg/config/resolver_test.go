@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"k8s.io/client-go/rest"
@@ -135,7 +136,10 @@ func Test_ResolvePolicyMapper(t *testing.T) {
 func Test_ResolveAPIServer(t *testing.T) {
 	resolver := config.NewResolver(testConfig, &rest.Config{})
 
-	server := resolver.APIServer(context.Background(), func() bool { return true })
+	server, err := resolver.APIServer(context.Background(), func() bool { return true })
+	if err != nil {
+		t.Errorf("Unexpected Error: %s", err)
+	}
 	if server == nil {
 		t.Error("Error: Should return API Server")
 	}
@@ -215,10 +219,131 @@ func Test_RegisterStoreListener(t *testing.T) {
 func Test_RegisterMetricsListener(t *testing.T) {
 	t.Run("Register MetricsListener", func(t *testing.T) {
 		resolver := config.NewResolver(testConfig, &rest.Config{})
-		resolver.RegisterMetricsListener()
+		if err := resolver.RegisterMetricsListener(); err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
 
-		if len(resolver.EventPublisher().GetListener()) != 1 {
-			t.Error("Expected one Listener to be registered")
+		if len(resolver.EventPublisher().GetListener()) != 3 {
+			t.Error("Expected the policy, effective action and ready Listener to be registered")
+		}
+	})
+}
+
+func Test_ResolvePolicyEventBroker(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{REST: config.REST{EventHistory: 10}}, &rest.Config{})
+
+	broker1 := resolver.PolicyEventBroker()
+	broker2 := resolver.PolicyEventBroker()
+
+	if broker1 != broker2 {
+		t.Error("A second call resolver.PolicyEventBroker() should return the cached first broker")
+	}
+}
+
+func Test_ResolveViolationStore(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{BlockReports: config.BlockReports{History: 10}}, &rest.Config{})
+
+	store1 := resolver.ViolationStore()
+	store2 := resolver.ViolationStore()
+
+	if store1 != store2 {
+		t.Error("A second call resolver.ViolationStore() should return the cached first store")
+	}
+}
+
+func Test_ResolveNamespaceAuthorizer(t *testing.T) {
+	t.Run("static", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{API: config.API{NamespaceAuthorization: config.NamespaceAuthorization{
+			Mode:     "static",
+			Mappings: []config.NamespaceMapping{{User: "tenant", Namespaces: []string{"team-a"}}},
+		}}}, &rest.Config{})
+
+		authorizer, err := resolver.NamespaceAuthorizer()
+		if err != nil {
+			t.Errorf("Unexpected Error: %s", err)
+		}
+		if authorizer == nil {
+			t.Error("Error: Should return a NamespaceAuthorizer")
 		}
 	})
+	t.Run("unknown mode", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{API: config.API{NamespaceAuthorization: config.NamespaceAuthorization{
+			Mode: "unknown",
+		}}}, &rest.Config{})
+
+		if _, err := resolver.NamespaceAuthorizer(); err == nil {
+			t.Error("Error: Should fail for an unknown mode")
+		}
+	})
+}
+
+func Test_ResolveBasicAuthCredentials(t *testing.T) {
+	t.Run("without users", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{}, &rest.Config{})
+
+		credentials, err := resolver.BasicAuthCredentials(context.Background())
+		if err != nil {
+			t.Errorf("Unexpected Error: %s", err)
+		}
+		if credentials != nil {
+			t.Error("Error: Should return no credentials without configured users")
+		}
+	})
+	t.Run("with users", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{API: config.API{BasicAuth: config.BasicAuth{
+			Username: "admin",
+			Password: "admin",
+			Users:    []config.BasicAuthUser{{Username: "prometheus", Password: "scrape", Scopes: []string{"metrics"}}},
+		}}}, &rest.Config{})
+
+		credentials, err := resolver.BasicAuthCredentials(context.Background())
+		if err != nil {
+			t.Errorf("Unexpected Error: %s", err)
+		}
+		if credentials == nil || credentials.Len() != 2 {
+			t.Error("Error: Should return credentials for both users")
+		}
+	})
+	t.Run("invalid scope", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{API: config.API{BasicAuth: config.BasicAuth{
+			Users: []config.BasicAuthUser{{Username: "prometheus", Password: "scrape", Scopes: []string{"admin"}}},
+		}}}, &rest.Config{})
+
+		if _, err := resolver.BasicAuthCredentials(context.Background()); err == nil {
+			t.Error("Error: Should fail for an unknown scope")
+		}
+	})
+}
+
+func Test_ResolveAPIServerWithInvalidTemplateDir(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{REST: config.REST{TemplateDir: "/not-exist"}}, &rest.Config{})
+
+	if _, err := resolver.APIServer(context.Background(), func() bool { return true }); err == nil {
+		t.Error("Error: Should fail for a not existing template directory")
+	}
+}
+
+func Test_ResolveAPIServerWithRequiredClientCertWithoutClientCA(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{API: config.API{TLS: config.TLS{
+		CertFile:          "tls.crt",
+		KeyFile:           "tls.key",
+		RequireClientCert: true,
+	}}}, &rest.Config{})
+
+	_, err := resolver.APIServer(context.Background(), func() bool { return true })
+	if err == nil || !strings.Contains(err.Error(), "requireClientCert") {
+		t.Errorf("Error: Should fail for requireClientCert without clientCAFile, got %v", err)
+	}
+}
+
+func Test_ResolveAPIServerWithImageCoverage(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{REST: config.REST{ImageCoverage: true}}, &rest.Config{})
+
+	server, err := resolver.APIServer(context.Background(), func() bool { return true })
+	if err != nil {
+		t.Errorf("Unexpected Error: %s", err)
+	}
+	if server == nil {
+		t.Error("Error: Should return API Server")
+	}
 }
@@ -0,0 +1,67 @@
+package kyverno
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/wildcard"
+)
+
+const (
+	ActionAudit   = "Audit"
+	ActionEnforce = "Enforce"
+)
+
+// NormalizeAction maps the deprecated lowercase actions, Audit is the default of Kyverno
+func NormalizeAction(action string) string {
+	if strings.EqualFold(action, ActionEnforce) {
+		return ActionEnforce
+	}
+
+	return ActionAudit
+}
+
+// Matches reports whether the override applies to the namespace. With namespaces and a selector both have to match.
+func (o *ValidationFailureActionOverride) Matches(namespace string, namespaceLabels map[string]string) bool {
+	if o.NamespaceSelector == nil {
+		_, ok := wildcard.MatchAny(o.Namespaces, namespace)
+		return ok
+	}
+
+	if !SelectorMatches(o.NamespaceSelector, namespaceLabels) {
+		return false
+	}
+
+	if len(o.Namespaces) == 0 {
+		return true
+	}
+
+	_, ok := wildcard.MatchAny(o.Namespaces, namespace)
+	return ok
+}
+
+// EffectiveAction is the validation failure action for resources in the namespace,
+// the first matching override of a ClusterPolicy replaces the spec level action
+func (p *Policy) EffectiveAction(namespace string, namespaceLabels map[string]string) string {
+	if p.Kind == ClusterPolicyKind {
+		for _, override := range p.ValidationFailureActionOverrides {
+			if override.Matches(namespace, namespaceLabels) {
+				return NormalizeAction(override.Action)
+			}
+		}
+	}
+
+	return NormalizeAction(p.ValidationFailureAction)
+}
+
+// SelectorMatches reports whether the labels match the selector, invalid selectors match nothing
+func SelectorMatches(selector *metav1.LabelSelector, set map[string]string) bool {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+
+	return s.Matches(labels.Set(set))
+}
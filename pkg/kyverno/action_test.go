@@ -0,0 +1,58 @@
+package kyverno_test
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+)
+
+func Test_EffectiveAction(t *testing.T) {
+	policy := kyverno.Policy{
+		Kind:                    kyverno.ClusterPolicyKind,
+		Name:                    "require-labels",
+		ValidationFailureAction: "audit",
+		ValidationFailureActionOverrides: []*kyverno.ValidationFailureActionOverride{
+			{Action: "enforce", Namespaces: []string{"prod-*"}},
+			{Action: "Enforce", NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+			{Action: "Audit", Namespaces: []string{"legacy"}, NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		namespace string
+		labels    map[string]string
+		expected  string
+	}{
+		{name: "default", namespace: "dev", expected: kyverno.ActionAudit},
+		{name: "namespace pattern", namespace: "prod-eu", expected: kyverno.ActionEnforce},
+		{name: "namespace selector", namespace: "shop", labels: map[string]string{"env": "prod"}, expected: kyverno.ActionEnforce},
+		{name: "selector without label", namespace: "shop", labels: map[string]string{"env": "dev"}, expected: kyverno.ActionAudit},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if action := policy.EffectiveAction(c.namespace, c.labels); action != c.expected {
+				t.Errorf("Expected %s, got %s", c.expected, action)
+			}
+		})
+	}
+
+	t.Run("namespaces and selector", func(t *testing.T) {
+		override := policy.ValidationFailureActionOverrides[2]
+		if !override.Matches("legacy", map[string]string{"env": "prod"}) || override.Matches("other", map[string]string{"env": "prod"}) {
+			t.Error("Expected namespaces and selector to match both")
+		}
+	})
+
+	t.Run("overrides of namespaced policies are ignored", func(t *testing.T) {
+		namespaced := policy
+		namespaced.Kind = kyverno.PolicyKind
+
+		if action := namespaced.EffectiveAction("prod-eu", nil); action != kyverno.ActionAudit {
+			t.Errorf("Expected Audit, got %s", action)
+		}
+	})
+}
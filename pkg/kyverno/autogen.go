@@ -0,0 +1,29 @@
+package kyverno
+
+import "strings"
+
+const (
+	autogenPrefix        = "autogen-"
+	autogenCronJobPrefix = "autogen-cronjob-"
+)
+
+// AutogenSourceRule returns the name of the rule an autogen rule was generated from,
+// other rule names are returned unchanged
+func AutogenSourceRule(rule string) string {
+	if strings.HasPrefix(rule, autogenCronJobPrefix) {
+		return strings.TrimPrefix(rule, autogenCronJobPrefix)
+	}
+
+	return strings.TrimPrefix(rule, autogenPrefix)
+}
+
+// AllRules returns the rules of the Policy followed by their autogen rules
+func (p *Policy) AllRules() []*Rule {
+	rules := make([]*Rule, 0, len(p.Rules))
+	for _, rule := range p.Rules {
+		rules = append(rules, rule)
+		rules = append(rules, rule.Autogen...)
+	}
+
+	return rules
+}
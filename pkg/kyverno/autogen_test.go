@@ -0,0 +1,43 @@
+package kyverno_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+)
+
+func Test_AutogenSourceRule(t *testing.T) {
+	cases := map[string]string{
+		"check-labels":                 "check-labels",
+		"autogen-check-labels":         "check-labels",
+		"autogen-cronjob-check-labels": "check-labels",
+	}
+
+	for rule, expected := range cases {
+		if source := kyverno.AutogenSourceRule(rule); source != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, rule, source)
+		}
+	}
+}
+
+func Test_AllRules(t *testing.T) {
+	policy := kyverno.Policy{Rules: []*kyverno.Rule{
+		{Name: "check-labels", Autogen: []*kyverno.Rule{{Name: "autogen-check-labels"}, {Name: "autogen-cronjob-check-labels"}}},
+		{Name: "check-image"},
+	}}
+
+	names := make([]string, 0)
+	for _, rule := range policy.AllRules() {
+		names = append(names, rule.Name)
+	}
+
+	expected := []string{"check-labels", "autogen-check-labels", "autogen-cronjob-check-labels", "check-image"}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, names)
+		}
+	}
+}
@@ -1,9 +1,14 @@
 package kubernetes
 
 import (
+	"bytes"
+	"encoding/json"
 	"strings"
 
+	"go.uber.org/zap"
 	"gopkg.in/yaml.v2"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	apiV1 "github.com/kyverno/policy-reporter-kyverno-plugin/pkg/crd/api/kyverno/v1"
@@ -51,33 +56,135 @@ func (m *mapper) MapPolicy(policy apiV1.PolicyInterface, content *unstructured.U
 	if policy.GetSpec() != nil {
 		r.Background = policy.GetSpec().Background
 		r.ValidationFailureAction = string(policy.GetSpec().ValidationFailureAction)
+		r.WebhookTimeoutSeconds = policy.GetSpec().WebhookTimeoutSeconds
+		r.SchemaValidation = policy.GetSpec().SchemaValidation
+		r.MutateExistingOnPolicyUpdate = policy.GetSpec().MutateExistingOnPolicyUpdate
+		r.GenerateExistingOnPolicyUpdate = policy.GetSpec().GenerateExistingOnPolicyUpdate
+
+		r.FailurePolicy = string(apiV1.Fail)
+		if policy.GetSpec().FailurePolicy != nil {
+			r.FailurePolicy = string(*policy.GetSpec().FailurePolicy)
+		}
+
+		r.ApplyRules = string(apiV1.ApplyAll)
+		if policy.GetSpec().ApplyRules != nil {
+			r.ApplyRules = string(*policy.GetSpec().ApplyRules)
+		}
+
+		for _, override := range policy.GetSpec().ValidationFailureActionOverrides {
+			r.ValidationFailureActionOverrides = append(r.ValidationFailureActionOverrides, &kyverno.ValidationFailureActionOverride{
+				Action:            string(override.Action),
+				Namespaces:        override.Namespaces,
+				NamespaceSelector: override.NamespaceSelector,
+			})
+		}
 
 		for _, rule := range policy.GetSpec().Rules {
 			r.Rules = append(r.Rules, m.mapRule(rule))
 		}
+
+		if status := policy.GetStatus(); status != nil {
+			m.mapAutogenRules(&r, status.Autogen.Rules)
+		}
 	}
 
 	r.Content = mapContent(content)
+	r.Status = mapStatus(policy.GetStatus())
 
 	return r
 }
 
+// mapAutogenRules adds the generated Pod controller rules to their source rule
+func (m *mapper) mapAutogenRules(policy *kyverno.Policy, rules []apiV1.Rule) {
+	for _, rule := range rules {
+		autogen := m.mapRule(rule)
+		source := kyverno.AutogenSourceRule(rule.Name)
+
+		found := false
+		for _, r := range policy.Rules {
+			if r.Name == source {
+				r.Autogen = append(r.Autogen, autogen)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			zap.L().Debug("source rule of autogen rule not found", zap.String("policy", policy.Name), zap.String("rule", rule.Name))
+		}
+	}
+}
+
+// mapStatus returns nil if Kyverno has not reconciled the Policy yet
+func mapStatus(status *apiV1.PolicyStatus) *kyverno.PolicyStatus {
+	if status == nil || (!status.Ready && len(status.Conditions) == 0) {
+		return nil
+	}
+
+	result := &kyverno.PolicyStatus{
+		Ready:      status.Ready,
+		Conditions: make([]*kyverno.PolicyCondition, 0, len(status.Conditions)),
+		RuleCount: kyverno.RuleCount{
+			Validate:     status.RuleCount.Validate,
+			Generate:     status.RuleCount.Generate,
+			Mutate:       status.RuleCount.Mutate,
+			VerifyImages: status.RuleCount.VerifyImages,
+		},
+	}
+
+	for _, condition := range status.Conditions {
+		result.Conditions = append(result.Conditions, &kyverno.PolicyCondition{
+			Type:               condition.Type,
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Time,
+		})
+
+		// the ready field is deprecated in favor of the Ready condition
+		if condition.Type == apiV1.PolicyConditionReady {
+			result.Ready = condition.Status == metav1.ConditionTrue
+		}
+	}
+
+	return result
+}
+
 func (m *mapper) mapRule(rule apiV1.Rule) *kyverno.Rule {
 	r := &kyverno.Rule{
-		Name: rule.Name,
+		Name:            rule.Name,
+		Match:           mapMatchResources(rule.MatchResources),
+		Exclude:         mapMatchResources(rule.ExcludeResources),
+		Preconditions:   mapPreconditions(rule.RawAnyAllConditions),
+		Context:         mapContext(rule.Context),
+		ImageExtractors: mapImageExtractors(rule.ImageExtractors),
 	}
 
 	if len(rule.VerifyImages) > 0 {
 		r.Type = "validation"
-		r.VerifyImages = make([]*kyverno.VerifyImage, 0)
-
 		r.VerifyImages = make([]*kyverno.VerifyImage, 0, len(rule.VerifyImages))
 
 		for _, verify := range rule.VerifyImages {
+			// Convert moves the deprecated image, key and keyless fields into imageReferences and attestors
+			converted := verify.Convert()
+
 			item := &kyverno.VerifyImage{
-				Repository: verify.Repository,
-				Image:      verify.Image,
-				Key:        strings.TrimSpace(verify.Key),
+				Repository:      verify.Repository,
+				Image:           verify.Image,
+				Key:             strings.TrimSpace(verify.Key),
+				ImageReferences: converted.ImageReferences,
+				Attestors:       mapAttestorSets(converted.Attestors),
+				Attestations:    make([]*kyverno.Attestation, 0, len(converted.Attestations)),
+				MutateDigest:    verify.MutateDigest,
+				VerifyDigest:    verify.VerifyDigest,
+				Required:        verify.Required,
+			}
+
+			for _, attestation := range converted.Attestations {
+				item.Attestations = append(item.Attestations, &kyverno.Attestation{
+					PredicateType: attestation.PredicateType,
+					Attestors:     mapAttestorSets(attestation.Attestors),
+				})
 			}
 
 			if item.Image == "" && len(verify.ImageReferences) > 0 {
@@ -107,6 +214,261 @@ func (m *mapper) mapRule(rule apiV1.Rule) *kyverno.Rule {
 	return r
 }
 
+func mapMatchResources(match apiV1.MatchResources) *kyverno.MatchResources {
+	result := &kyverno.MatchResources{
+		Any: mapResourceFilters(match.Any),
+		All: mapResourceFilters(match.All),
+	}
+
+	if filter := mapResourceFilter(apiV1.ResourceFilter{UserInfo: match.UserInfo, ResourceDescription: match.ResourceDescription}); filter != nil {
+		result.All = append(result.All, filter)
+	}
+
+	if len(result.Any) == 0 && len(result.All) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+func mapResourceFilters(filters apiV1.ResourceFilters) []*kyverno.ResourceFilter {
+	list := make([]*kyverno.ResourceFilter, 0, len(filters))
+	for _, filter := range filters {
+		if item := mapResourceFilter(filter); item != nil {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// mapResourceFilter returns nil for empty filters
+func mapResourceFilter(filter apiV1.ResourceFilter) *kyverno.ResourceFilter {
+	result := &kyverno.ResourceFilter{
+		Roles:        filter.Roles,
+		ClusterRoles: filter.ClusterRoles,
+	}
+
+	for _, subject := range filter.Subjects {
+		result.Subjects = append(result.Subjects, kyverno.Subject{Kind: subject.Kind, Name: subject.Name, Namespace: subject.Namespace})
+	}
+
+	description := filter.ResourceDescription
+	if !emptyResourceDescription(description) {
+		result.Resources = &kyverno.ResourceDescription{
+			Kinds:             description.Kinds,
+			Names:             description.Names,
+			Namespaces:        description.Namespaces,
+			Annotations:       description.Annotations,
+			Selector:          description.Selector,
+			NamespaceSelector: description.NamespaceSelector,
+		}
+
+		// the deprecated name is the same as a single entry of names
+		if description.Name != "" {
+			result.Resources.Names = append([]string{description.Name}, description.Names...)
+		}
+	}
+
+	if result.Resources == nil && len(result.Roles) == 0 && len(result.ClusterRoles) == 0 && len(result.Subjects) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+func emptyResourceDescription(d apiV1.ResourceDescription) bool {
+	return len(d.Kinds) == 0 && d.Name == "" && len(d.Names) == 0 && len(d.Namespaces) == 0 &&
+		len(d.Annotations) == 0 && d.Selector == nil && d.NamespaceSelector == nil
+}
+
+// mapPreconditions supports any/all conditions and the deprecated plain condition list
+func mapPreconditions(raw *apiextv1.JSON) *kyverno.Conditions {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil
+	}
+
+	conditions := apiV1.AnyAllConditions{}
+	if bytes.HasPrefix(bytes.TrimSpace(raw.Raw), []byte("[")) {
+		if err := json.Unmarshal(raw.Raw, &conditions.AllConditions); err != nil {
+			zap.L().Error("failed to map preconditions", zap.Error(err))
+			return nil
+		}
+	} else if err := json.Unmarshal(raw.Raw, &conditions); err != nil {
+		zap.L().Error("failed to map preconditions", zap.Error(err))
+		return nil
+	}
+
+	if len(conditions.AnyConditions) == 0 && len(conditions.AllConditions) == 0 {
+		return nil
+	}
+
+	return &kyverno.Conditions{
+		Any: mapConditions(conditions.AnyConditions),
+		All: mapConditions(conditions.AllConditions),
+	}
+}
+
+func mapConditions(conditions []apiV1.Condition) []*kyverno.Condition {
+	list := make([]*kyverno.Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		list = append(list, &kyverno.Condition{
+			Key:      jsonValue(condition.RawKey),
+			Operator: string(condition.Operator),
+			Value:    jsonValue(condition.RawValue),
+		})
+	}
+
+	return list
+}
+
+func mapContext(entries []apiV1.ContextEntry) []*kyverno.ContextEntry {
+	list := make([]*kyverno.ContextEntry, 0, len(entries))
+	for _, entry := range entries {
+		item := &kyverno.ContextEntry{Name: entry.Name}
+
+		switch {
+		case entry.ConfigMap != nil:
+			item.Type = "configMap"
+			item.ConfigMap = &kyverno.ConfigMapReference{Name: entry.ConfigMap.Name, Namespace: entry.ConfigMap.Namespace}
+		case entry.APICall != nil:
+			item.Type = "apiCall"
+			item.APICall = &kyverno.APICall{URLPath: entry.APICall.URLPath, JMESPath: entry.APICall.JMESPath}
+
+			if service := entry.APICall.Service; service != nil {
+				item.APICall.Service = &kyverno.ServiceCall{URL: service.URL, Method: string(service.Method)}
+
+				for _, data := range service.Data {
+					item.APICall.Service.Data = append(item.APICall.Service.Data, kyverno.RequestData{Key: data.Key, Value: jsonValue(data.Value)})
+				}
+			}
+		case entry.ImageRegistry != nil:
+			item.Type = "imageRegistry"
+			item.ImageRegistry = &kyverno.ImageRegistry{Reference: entry.ImageRegistry.Reference, JMESPath: entry.ImageRegistry.JMESPath}
+		case entry.Variable != nil:
+			item.Type = "variable"
+			item.Variable = &kyverno.Variable{
+				Value:    jsonValue(entry.Variable.Value),
+				JMESPath: entry.Variable.JMESPath,
+				Default:  jsonValue(entry.Variable.Default),
+			}
+		}
+
+		list = append(list, item)
+	}
+
+	return list
+}
+
+func mapImageExtractors(configs apiV1.ImageExtractorConfigs) map[string][]*kyverno.ImageExtractor {
+	if len(configs) == 0 {
+		return nil
+	}
+
+	result := make(map[string][]*kyverno.ImageExtractor, len(configs))
+	for kind, extractors := range configs {
+		for _, extractor := range extractors {
+			result[kind] = append(result[kind], &kyverno.ImageExtractor{
+				Path:  extractor.Path,
+				Value: extractor.Value,
+				Name:  extractor.Name,
+				Key:   extractor.Key,
+			})
+		}
+	}
+
+	return result
+}
+
+// jsonValue decodes raw JSON values, invalid values are returned as string
+func jsonValue(raw *apiextv1.JSON) any {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(raw.Raw, &value); err != nil {
+		return string(raw.Raw)
+	}
+
+	return value
+}
+
+func mapAttestorSets(sets []apiV1.AttestorSet) []*kyverno.AttestorSet {
+	list := make([]*kyverno.AttestorSet, 0, len(sets))
+	for _, set := range sets {
+		list = append(list, mapAttestorSet(set))
+	}
+
+	return list
+}
+
+func mapAttestorSet(set apiV1.AttestorSet) *kyverno.AttestorSet {
+	result := &kyverno.AttestorSet{
+		Count:   set.Count,
+		Entries: make([]*kyverno.Attestor, 0, len(set.Entries)),
+	}
+
+	for _, entry := range set.Entries {
+		attestor := &kyverno.Attestor{
+			Annotations: entry.Annotations,
+			Repository:  entry.Repository,
+		}
+
+		if entry.Keys != nil {
+			attestor.Keys = &kyverno.StaticKeyAttestor{
+				PublicKeys:         strings.TrimSpace(entry.Keys.PublicKeys),
+				SignatureAlgorithm: entry.Keys.SignatureAlgorithm,
+				KMS:                entry.Keys.KMS,
+				Rekor:              rekorURL(entry.Keys.Rekor),
+			}
+
+			if entry.Keys.Secret != nil {
+				attestor.Keys.Secret = &kyverno.SecretReference{Name: entry.Keys.Secret.Name, Namespace: entry.Keys.Secret.Namespace}
+			}
+		}
+
+		if entry.Certificates != nil {
+			attestor.Certificates = &kyverno.CertificateAttestor{
+				Certificate:      strings.TrimSpace(entry.Certificates.Certificate),
+				CertificateChain: strings.TrimSpace(entry.Certificates.CertificateChain),
+				Rekor:            rekorURL(entry.Certificates.Rekor),
+			}
+		}
+
+		if entry.Keyless != nil {
+			attestor.Keyless = &kyverno.KeylessAttestor{
+				Subject:              entry.Keyless.Subject,
+				Issuer:               entry.Keyless.Issuer,
+				Roots:                strings.TrimSpace(entry.Keyless.Roots),
+				Rekor:                rekorURL(entry.Keyless.Rekor),
+				AdditionalExtensions: entry.Keyless.AdditionalExtensions,
+			}
+		}
+
+		if entry.Attestor != nil {
+			nested, err := apiV1.AttestorSetUnmarshal(entry.Attestor)
+			if err != nil {
+				zap.L().Error("failed to map nested attestor", zap.Error(err))
+			} else {
+				attestor.Attestor = mapAttestorSet(*nested)
+			}
+		}
+
+		result.Entries = append(result.Entries, attestor)
+	}
+
+	return result
+}
+
+func rekorURL(log *apiV1.CTLog) string {
+	if log == nil {
+		return ""
+	}
+
+	return log.URL
+}
+
 func mapContent(policy *unstructured.Unstructured) string {
 	if policy == nil {
 		return ""
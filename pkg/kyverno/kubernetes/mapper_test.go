@@ -0,0 +1,299 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/kyverno/policy-reporter-kyverno-plugin/pkg/crd/api/kyverno/v1"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno/kubernetes"
+)
+
+func Test_MapVerifyImages(t *testing.T) {
+	count := 1
+
+	policy := &apiV1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "verify-images"},
+		Spec: apiV1.Spec{
+			Rules: []apiV1.Rule{
+				{
+					Name: "legacy",
+					VerifyImages: []apiV1.ImageVerification{{
+						Image: "ghcr.io/kyverno/*",
+						Key:   " -----BEGIN PUBLIC KEY----- ",
+					}},
+				},
+				{
+					Name: "attestors",
+					VerifyImages: []apiV1.ImageVerification{{
+						ImageReferences: []string{"ghcr.io/kyverno/*", "docker.io/kyverno/*"},
+						Attestors: []apiV1.AttestorSet{{
+							Count: &count,
+							Entries: []apiV1.Attestor{
+								{Keyless: &apiV1.KeylessAttestor{Subject: "https://github.com/kyverno/*", Issuer: "https://token.actions.githubusercontent.com", Rekor: &apiV1.CTLog{URL: "https://rekor.sigstore.dev"}}},
+								{Keys: &apiV1.StaticKeyAttestor{Secret: &apiV1.SecretReference{Name: "cosign", Namespace: "kyverno"}}},
+								{Attestor: &apiextv1.JSON{Raw: []byte(`{"entries":[{"certificates":{"cert":"cert"}}]}`)}},
+							},
+						}},
+						Attestations: []apiV1.Attestation{{PredicateType: "https://slsa.dev/provenance/v0.2"}},
+						MutateDigest: true,
+						VerifyDigest: true,
+						Required:     true,
+					}},
+				},
+			},
+		},
+	}
+
+	result := kubernetes.NewMapper().MapPolicy(policy, nil)
+
+	legacy := result.Rules[0].VerifyImages[0]
+	if legacy.Image != "ghcr.io/kyverno/*" || len(legacy.ImageReferences) != 1 || legacy.ImageReferences[0] != "ghcr.io/kyverno/*" {
+		t.Errorf("Expected the deprecated image as image reference, got %+v", legacy)
+	}
+	if len(legacy.Attestors) != 1 || legacy.Attestors[0].Entries[0].Keys.PublicKeys != "-----BEGIN PUBLIC KEY-----" {
+		t.Errorf("Expected the deprecated key as static key attestor, got %+v", legacy.Attestors)
+	}
+
+	verify := result.Rules[1].VerifyImages[0]
+	if result.Rules[1].Type != "validation" {
+		t.Errorf("Unexpected rule type: %s", result.Rules[1].Type)
+	}
+	if len(verify.ImageReferences) != 2 || !verify.MutateDigest || !verify.VerifyDigest || !verify.Required {
+		t.Errorf("Unexpected image verification: %+v", verify)
+	}
+
+	set := verify.Attestors[0]
+	if set.Count == nil || *set.Count != 1 || len(set.Entries) != 3 {
+		t.Fatalf("Unexpected attestor set: %+v", set)
+	}
+	if set.Entries[0].Keyless.Rekor != "https://rekor.sigstore.dev" || set.Entries[0].Keyless.Subject != "https://github.com/kyverno/*" {
+		t.Errorf("Unexpected keyless attestor: %+v", set.Entries[0].Keyless)
+	}
+	if set.Entries[1].Keys.Secret == nil || set.Entries[1].Keys.Secret.Name != "cosign" {
+		t.Errorf("Unexpected static key attestor: %+v", set.Entries[1].Keys)
+	}
+	if set.Entries[2].Attestor == nil || set.Entries[2].Attestor.Entries[0].Certificates.Certificate != "cert" {
+		t.Errorf("Unexpected nested attestor: %+v", set.Entries[2].Attestor)
+	}
+
+	if len(verify.Attestations) != 1 || verify.Attestations[0].PredicateType != "https://slsa.dev/provenance/v0.2" {
+		t.Errorf("Unexpected attestations: %+v", verify.Attestations)
+	}
+}
+
+func Test_MapRuleMatchConditionsAndContext(t *testing.T) {
+	policy := &apiV1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "require-labels"},
+		Spec: apiV1.Spec{
+			ValidationFailureAction: "Audit",
+			ValidationFailureActionOverrides: []apiV1.ValidationFailureActionOverride{
+				{Action: "Enforce", Namespaces: []string{"prod"}},
+			},
+			Rules: []apiV1.Rule{
+				{
+					Name: "check-team",
+					MatchResources: apiV1.MatchResources{
+						Any: apiV1.ResourceFilters{
+							{ResourceDescription: apiV1.ResourceDescription{Kinds: []string{"Pod"}, NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}}},
+							{UserInfo: apiV1.UserInfo{ClusterRoles: []string{"cluster-admin"}}},
+						},
+					},
+					ExcludeResources: apiV1.MatchResources{
+						ResourceDescription: apiV1.ResourceDescription{Name: "legacy", Namespaces: []string{"kube-system"}},
+					},
+					RawAnyAllConditions: &apiextv1.JSON{Raw: []byte(`[{"key":"{{ request.operation }}","operator":"NotEquals","value":"DELETE"}]`)},
+					Context: []apiV1.ContextEntry{
+						{Name: "teams", ConfigMap: &apiV1.ConfigMapReference{Name: "teams", Namespace: "kyverno"}},
+						{Name: "pods", APICall: &apiV1.APICall{URLPath: "/api/v1/pods", JMESPath: "items | length(@)"}},
+						{Name: "limit", Variable: &apiV1.Variable{Value: &apiextv1.JSON{Raw: []byte(`5`)}}},
+					},
+					ImageExtractors: apiV1.ImageExtractorConfigs{"Task": {{Path: "/spec/steps/*/image"}}},
+					Validation:      apiV1.Validation{Message: "team label required"},
+				},
+				{
+					Name:                "any-all",
+					MatchResources:      apiV1.MatchResources{All: apiV1.ResourceFilters{{ResourceDescription: apiV1.ResourceDescription{Kinds: []string{"Service"}}}}},
+					RawAnyAllConditions: &apiextv1.JSON{Raw: []byte(`{"any":[{"key":"{{ request.object.spec.type }}","operator":"Equals","value":"LoadBalancer"}]}`)},
+				},
+			},
+		},
+	}
+
+	result := kubernetes.NewMapper().MapPolicy(policy, nil)
+
+	if overrides := result.ValidationFailureActionOverrides; len(overrides) != 1 || overrides[0].Action != "Enforce" || overrides[0].Namespaces[0] != "prod" {
+		t.Errorf("Unexpected validationFailureActionOverrides: %+v", overrides)
+	}
+
+	rule := result.Rules[0]
+	if rule.Type != "validation" || rule.ValidateMessage != "team label required" {
+		t.Errorf("Unexpected rule type or message: %+v", rule)
+	}
+
+	if rule.Match == nil || len(rule.Match.Any) != 2 || len(rule.Match.All) != 0 {
+		t.Fatalf("Unexpected match: %+v", rule.Match)
+	}
+	if resources := rule.Match.Any[0].Resources; resources.Kinds[0] != "Pod" || resources.NamespaceSelector.MatchLabels["env"] != "prod" {
+		t.Errorf("Unexpected match resources: %+v", resources)
+	}
+	if filter := rule.Match.Any[1]; filter.Resources != nil || filter.ClusterRoles[0] != "cluster-admin" {
+		t.Errorf("Unexpected user info filter: %+v", filter)
+	}
+
+	if rule.Exclude == nil || len(rule.Exclude.All) != 1 {
+		t.Fatalf("Expected the deprecated exclude resources as all filter, got %+v", rule.Exclude)
+	}
+	if resources := rule.Exclude.All[0].Resources; resources.Names[0] != "legacy" || resources.Namespaces[0] != "kube-system" {
+		t.Errorf("Unexpected exclude resources: %+v", resources)
+	}
+
+	if rule.Preconditions == nil || len(rule.Preconditions.All) != 1 || rule.Preconditions.All[0].Value != "DELETE" {
+		t.Errorf("Expected the deprecated condition list as all conditions, got %+v", rule.Preconditions)
+	}
+
+	if len(rule.Context) != 3 {
+		t.Fatalf("Expected 3 context entries, got %d", len(rule.Context))
+	}
+	if entry := rule.Context[0]; entry.Type != "configMap" || entry.ConfigMap.Namespace != "kyverno" {
+		t.Errorf("Unexpected configMap entry: %+v", entry)
+	}
+	if entry := rule.Context[1]; entry.Type != "apiCall" || entry.APICall.URLPath != "/api/v1/pods" {
+		t.Errorf("Unexpected apiCall entry: %+v", entry)
+	}
+	if entry := rule.Context[2]; entry.Type != "variable" || entry.Variable.Value != float64(5) {
+		t.Errorf("Unexpected variable entry: %+v", entry)
+	}
+
+	if extractors := rule.ImageExtractors["Task"]; len(extractors) != 1 || extractors[0].Path != "/spec/steps/*/image" {
+		t.Errorf("Unexpected image extractors: %+v", rule.ImageExtractors)
+	}
+
+	anyAll := result.Rules[1]
+	if anyAll.Exclude != nil {
+		t.Errorf("Expected no exclude block, got %+v", anyAll.Exclude)
+	}
+	if anyAll.Match == nil || len(anyAll.Match.All) != 1 {
+		t.Errorf("Unexpected match: %+v", anyAll.Match)
+	}
+	if anyAll.Preconditions == nil || len(anyAll.Preconditions.Any) != 1 || anyAll.Preconditions.Any[0].Operator != "Equals" {
+		t.Errorf("Unexpected preconditions: %+v", anyAll.Preconditions)
+	}
+}
+
+func Test_MapStatus(t *testing.T) {
+	policy := &apiV1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "require-labels", Namespace: "test"},
+		Status: apiV1.PolicyStatus{
+			Ready: true,
+			Conditions: []metav1.Condition{
+				{Type: apiV1.PolicyConditionReady, Status: metav1.ConditionFalse, Reason: apiV1.PolicyReasonFailed, Message: "failed to load context"},
+			},
+			RuleCount: apiV1.RuleCountStatus{Validate: 2, VerifyImages: 1},
+		},
+	}
+
+	status := kubernetes.NewMapper().MapPolicy(policy, nil).Status
+	if status == nil {
+		t.Fatal("Expected mapped status")
+	}
+
+	if status.Ready {
+		t.Error("Expected the Ready condition to take precedence over the deprecated ready field")
+	}
+	if len(status.Conditions) != 1 || status.Conditions[0].Reason != "Failed" || status.Conditions[0].Message != "failed to load context" {
+		t.Errorf("Unexpected conditions: %+v", status.Conditions)
+	}
+	if status.RuleCount.Validate != 2 || status.RuleCount.VerifyImages != 1 {
+		t.Errorf("Unexpected rule count: %+v", status.RuleCount)
+	}
+
+	pending := kubernetes.NewMapper().MapPolicy(&apiV1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "require-labels", Namespace: "test"}}, nil)
+	if pending.Status != nil {
+		t.Errorf("Expected no status for a Policy not reconciled by Kyverno, got %+v", pending.Status)
+	}
+}
+
+func Test_MapAutogenRules(t *testing.T) {
+	match := func(kinds ...string) apiV1.MatchResources {
+		return apiV1.MatchResources{Any: apiV1.ResourceFilters{{ResourceDescription: apiV1.ResourceDescription{Kinds: kinds}}}}
+	}
+
+	policy := &apiV1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "require-labels"},
+		Spec: apiV1.Spec{
+			Rules: []apiV1.Rule{
+				{Name: "check-labels", MatchResources: match("Pod"), Validation: apiV1.Validation{Message: "labels required"}},
+				{Name: "check-image", MatchResources: match("Pod"), Validation: apiV1.Validation{Message: "image required"}},
+			},
+		},
+		Status: apiV1.PolicyStatus{
+			Autogen: apiV1.AutogenStatus{Rules: []apiV1.Rule{
+				{Name: "autogen-check-labels", MatchResources: match("Deployment", "StatefulSet"), Validation: apiV1.Validation{Message: "labels required"}},
+				{Name: "autogen-cronjob-check-labels", MatchResources: match("CronJob"), Validation: apiV1.Validation{Message: "labels required"}},
+			}},
+		},
+	}
+
+	result := kubernetes.NewMapper().MapPolicy(policy, nil)
+
+	if len(result.Rules) != 2 {
+		t.Fatalf("Expected autogen rules not to be top level rules, got %d rules", len(result.Rules))
+	}
+
+	autogen := result.Rules[0].Autogen
+	if len(autogen) != 2 || autogen[0].Name != "autogen-check-labels" || autogen[1].Name != "autogen-cronjob-check-labels" {
+		t.Fatalf("Expected autogen rules as children of check-labels, got %+v", autogen)
+	}
+	if autogen[0].Type != "validation" || autogen[0].Match.Any[0].Resources.Kinds[1] != "StatefulSet" {
+		t.Errorf("Unexpected autogen rule: %+v", autogen[0])
+	}
+
+	if len(result.Rules[1].Autogen) != 0 {
+		t.Errorf("Expected no autogen rules for check-image, got %+v", result.Rules[1].Autogen)
+	}
+}
+
+func Test_MapAdmissionSettings(t *testing.T) {
+	ignore := apiV1.Ignore
+	one := apiV1.ApplyOne
+	timeout := int32(15)
+	schemaValidation := false
+
+	policy := &apiV1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "add-labels"},
+		Spec: apiV1.Spec{
+			FailurePolicy:                  &ignore,
+			ApplyRules:                     &one,
+			WebhookTimeoutSeconds:          &timeout,
+			SchemaValidation:               &schemaValidation,
+			MutateExistingOnPolicyUpdate:   true,
+			GenerateExistingOnPolicyUpdate: true,
+		},
+	}
+
+	result := kubernetes.NewMapper().MapPolicy(policy, nil)
+
+	if result.FailurePolicy != "Ignore" || result.ApplyRules != "One" {
+		t.Errorf("Unexpected failurePolicy or applyRules: %s, %s", result.FailurePolicy, result.ApplyRules)
+	}
+	if result.WebhookTimeoutSeconds == nil || *result.WebhookTimeoutSeconds != 15 {
+		t.Errorf("Unexpected webhookTimeoutSeconds: %v", result.WebhookTimeoutSeconds)
+	}
+	if result.SchemaValidation == nil || *result.SchemaValidation {
+		t.Errorf("Unexpected schemaValidation: %v", result.SchemaValidation)
+	}
+	if !result.MutateExistingOnPolicyUpdate || !result.GenerateExistingOnPolicyUpdate {
+		t.Errorf("Expected mutate and generate existing on policy update")
+	}
+
+	result = kubernetes.NewMapper().MapPolicy(&apiV1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "defaults"}}, nil)
+
+	if result.FailurePolicy != "Fail" || result.ApplyRules != "All" {
+		t.Errorf("Expected Kyverno defaults, got %s, %s", result.FailurePolicy, result.ApplyRules)
+	}
+	if result.WebhookTimeoutSeconds != nil || result.SchemaValidation != nil {
+		t.Errorf("Expected unset webhookTimeoutSeconds and schemaValidation")
+	}
+}
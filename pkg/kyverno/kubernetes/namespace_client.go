@@ -0,0 +1,77 @@
+package kubernetes
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NamespaceClient caches the labels of all namespaces
+type NamespaceClient interface {
+	// Run starts the informer and waits for the initial sync
+	Run(stopper chan struct{}) error
+	// Labels returns the labels per namespace name
+	Labels() map[string]map[string]string
+	// NamespaceLabels returns the labels of a single namespace, false if it does not exist
+	NamespaceLabels(name string) (map[string]string, bool)
+}
+
+type namespaceClient struct {
+	factory metadatainformer.SharedInformerFactory
+	ns      informers.GenericInformer
+}
+
+func (c *namespaceClient) Run(stopper chan struct{}) error {
+	informer := c.ns.Informer()
+
+	c.factory.Start(stopper)
+
+	if !cache.WaitForCacheSync(stopper, informer.HasSynced) {
+		return fmt.Errorf("failed to sync namespaces")
+	}
+
+	return nil
+}
+
+func (c *namespaceClient) Labels() map[string]map[string]string {
+	items := c.ns.Informer().GetStore().List()
+
+	namespaces := make(map[string]map[string]string, len(items))
+	for _, obj := range items {
+		if item, ok := obj.(*v1.PartialObjectMetadata); ok {
+			namespaces[item.Name] = item.Labels
+		}
+	}
+
+	return namespaces
+}
+
+func (c *namespaceClient) NamespaceLabels(name string) (map[string]string, bool) {
+	obj, exists, err := c.ns.Informer().GetStore().GetByKey(name)
+	if err != nil || !exists {
+		return nil, false
+	}
+
+	item, ok := obj.(*v1.PartialObjectMetadata)
+	if !ok {
+		return nil, false
+	}
+
+	return item.Labels, true
+}
+
+// NewNamespaceClient creates a new NamespaceClient based on the kubernetes metadata client
+func NewNamespaceClient(client metadata.Interface) NamespaceClient {
+	factory := metadatainformer.NewSharedInformerFactory(client, 15*time.Minute)
+
+	return &namespaceClient{
+		factory: factory,
+		ns:      factory.ForResource(corev1.SchemeGroupVersion.WithResource("namespaces")),
+	}
+}
@@ -21,7 +21,7 @@ type Cache struct {
 
 func (c *Cache) Add(polr kyverno.Policy) {
 	labels := map[string]*CacheItem{}
-	for _, res := range polr.Rules {
+	for _, res := range polr.AllRules() {
 		l := generateResultLabels(polr, res)
 
 		hash := labelHash(l)
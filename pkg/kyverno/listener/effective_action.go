@@ -0,0 +1,86 @@
+package listener
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+)
+
+// NamespaceLabels provides the labels per namespace name
+type NamespaceLabels interface {
+	Labels() map[string]map[string]string
+}
+
+// effectiveActionCollector evaluates the effective action of all validation policies for the
+// current namespaces on each scrape, so namespace label changes are reflected without policy events
+type effectiveActionCollector struct {
+	mx         sync.RWMutex
+	policies   map[string]kyverno.Policy
+	namespaces NamespaceLabels
+	desc       *prometheus.Desc
+}
+
+func (c *effectiveActionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *effectiveActionCollector) Collect(ch chan<- prometheus.Metric) {
+	namespaces := c.namespaces.Labels()
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	for _, policy := range c.policies {
+		if policy.Namespace != "" {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, 1, policy.Namespace, policy.Kind, policy.Name, policy.EffectiveAction(policy.Namespace, namespaces[policy.Namespace]))
+			continue
+		}
+
+		for namespace, labels := range namespaces {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, 1, namespace, policy.Kind, policy.Name, policy.EffectiveAction(namespace, labels))
+		}
+	}
+}
+
+func (c *effectiveActionCollector) handle(event kyverno.LifecycleEvent) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if event.Type == kyverno.Deleted || !hasValidation(event.Policy) {
+		delete(c.policies, event.Policy.GetID())
+		return
+	}
+
+	c.policies[event.Policy.GetID()] = event.Policy
+}
+
+// hasValidation checks for rules using the validation failure action
+func hasValidation(policy kyverno.Policy) bool {
+	for _, rule := range policy.Rules {
+		if rule.Type == "validation" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewEffectiveActionMetricsListener for the effective validation failure action of each Policy per namespace
+func NewEffectiveActionMetricsListener(namespaces NamespaceLabels) kyverno.PolicyListener {
+	collector := &effectiveActionCollector{
+		policies:   make(map[string]kyverno.Policy),
+		namespaces: namespaces,
+		desc: prometheus.NewDesc(
+			"kyverno_policy_effective_action",
+			"Effective validationFailureAction of a Policy per namespace including validationFailureActionOverrides",
+			[]string{"namespace", "kind", "policy", "action"},
+			nil,
+		),
+	}
+
+	prometheus.Register(collector)
+
+	return collector.handle
+}
@@ -0,0 +1,89 @@
+package listener_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno/listener"
+)
+
+type namespaceLabels map[string]map[string]string
+
+func (n namespaceLabels) Labels() map[string]map[string]string {
+	return n
+}
+
+func metricLabels(metric *io_prometheus_client.Metric) map[string]string {
+	labels := make(map[string]string, len(metric.Label))
+	for _, label := range metric.Label {
+		labels[*label.Name] = *label.Value
+	}
+
+	return labels
+}
+
+func Test_EffectiveActionMetricGeneration(t *testing.T) {
+	handler := listener.NewEffectiveActionMetricsListener(namespaceLabels{
+		"dev":  {"env": "dev"},
+		"prod": {"env": "prod"},
+	})
+
+	clusterPolicy := kyverno.Policy{
+		Kind:                    kyverno.ClusterPolicyKind,
+		Name:                    "require-labels",
+		ValidationFailureAction: "Audit",
+		ValidationFailureActionOverrides: []*kyverno.ValidationFailureActionOverride{
+			{Action: "Enforce", NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+		},
+		Rules: []*kyverno.Rule{{Name: "check-labels", Type: "validation"}},
+	}
+	policy := kyverno.Policy{Kind: kyverno.PolicyKind, Name: "team", Namespace: "dev", ValidationFailureAction: "enforce", Rules: []*kyverno.Rule{{Name: "check-image", Type: "validation"}}}
+	mutation := kyverno.Policy{Kind: kyverno.ClusterPolicyKind, Name: "add-labels", Rules: []*kyverno.Rule{{Name: "add-labels", Type: "mutation"}}}
+
+	t.Run("Added Metric", func(t *testing.T) {
+		handler(kyverno.LifecycleEvent{Type: kyverno.Added, Policy: clusterPolicy})
+		handler(kyverno.LifecycleEvent{Type: kyverno.Added, Policy: policy})
+		handler(kyverno.LifecycleEvent{Type: kyverno.Added, Policy: mutation})
+
+		metricFam, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		results := findMetric(metricFam, "kyverno_policy_effective_action")
+		if results == nil || len(results.GetMetric()) != 3 {
+			t.Fatalf("Expected 3 metrics, got %v", results)
+		}
+
+		actions := make(map[string]string)
+		for _, metric := range results.GetMetric() {
+			labels := metricLabels(metric)
+			actions[labels["policy"]+"/"+labels["namespace"]] = labels["action"]
+		}
+
+		if actions["require-labels/dev"] != kyverno.ActionAudit || actions["require-labels/prod"] != kyverno.ActionEnforce {
+			t.Errorf("Unexpected ClusterPolicy actions: %v", actions)
+		}
+		if actions["team/dev"] != kyverno.ActionEnforce {
+			t.Errorf("Unexpected Policy action: %v", actions)
+		}
+	})
+
+	t.Run("Deleted Metric", func(t *testing.T) {
+		handler(kyverno.LifecycleEvent{Type: kyverno.Deleted, Policy: clusterPolicy})
+		handler(kyverno.LifecycleEvent{Type: kyverno.Deleted, Policy: policy})
+
+		metricFam, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if results := findMetric(metricFam, "kyverno_policy_effective_action"); results != nil {
+			t.Error("kyverno_policy_effective_action should no longer exist")
+		}
+	})
+}
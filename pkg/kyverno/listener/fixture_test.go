@@ -13,6 +13,11 @@ func NewPolicy() kyverno.Policy {
 		Background: func(val bool) *bool {
 			return &val
 		}(false),
+		FailurePolicy:                "Ignore",
+		ApplyRules:                   "All",
+		WebhookTimeoutSeconds:        func(val int32) *int32 { return &val }(5),
+		SchemaValidation:             func(val bool) *bool { return &val }(true),
+		MutateExistingOnPolicyUpdate: true,
 		Rules: []*kyverno.Rule{
 			{
 				Name:            "host-path",
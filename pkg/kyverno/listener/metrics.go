@@ -14,7 +14,8 @@ func NewPolicyMetricsListener() kyverno.PolicyListener {
 	policyGauge := promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "kyverno_policy",
 		Help: "List of all Policies",
-	}, []string{"namespace", "kind", "policy", "rule", "type", "background", "severity", "category", "validationFailureAction"})
+	}, []string{"namespace", "kind", "policy", "rule", "type", "background", "severity", "category", "validationFailureAction",
+		"failurePolicy", "webhookTimeoutSeconds", "applyRules", "schemaValidation", "mutateExistingOnPolicyUpdate", "generateExistingOnPolicyUpdate"})
 
 	prometheus.Register(policyGauge)
 	cache := NewCache()
@@ -22,7 +23,7 @@ func NewPolicyMetricsListener() kyverno.PolicyListener {
 	return func(event kyverno.LifecycleEvent) {
 		switch event.Type {
 		case kyverno.Added:
-			for _, rule := range event.Policy.Rules {
+			for _, rule := range event.Policy.AllRules() {
 				policyGauge.With(generateResultLabels(event.Policy, rule)).Set(1)
 			}
 
@@ -33,7 +34,7 @@ func NewPolicyMetricsListener() kyverno.PolicyListener {
 				policyGauge.Delete(rule.Labels)
 			}
 
-			for _, rule := range event.Policy.Rules {
+			for _, rule := range event.Policy.AllRules() {
 				policyGauge.With(generateResultLabels(event.Policy, rule)).Set(1)
 			}
 
@@ -52,20 +53,34 @@ func NewPolicyMetricsListener() kyverno.PolicyListener {
 
 func generateResultLabels(policy kyverno.Policy, rule *kyverno.Rule) prometheus.Labels {
 	labels := prometheus.Labels{
-		"namespace":               policy.Namespace,
-		"kind":                    policy.Kind,
-		"policy":                  policy.Name,
-		"severity":                policy.Severity,
-		"category":                policy.Category,
-		"background":              "",
-		"validationFailureAction": policy.ValidationFailureAction,
-		"rule":                    rule.Name,
-		"type":                    rule.Type,
+		"namespace":                      policy.Namespace,
+		"kind":                           policy.Kind,
+		"policy":                         policy.Name,
+		"severity":                       policy.Severity,
+		"category":                       policy.Category,
+		"background":                     "",
+		"validationFailureAction":        policy.ValidationFailureAction,
+		"rule":                           rule.Name,
+		"type":                           rule.Type,
+		"failurePolicy":                  policy.FailurePolicy,
+		"webhookTimeoutSeconds":          "",
+		"applyRules":                     policy.ApplyRules,
+		"schemaValidation":               "",
+		"mutateExistingOnPolicyUpdate":   strconv.FormatBool(policy.MutateExistingOnPolicyUpdate),
+		"generateExistingOnPolicyUpdate": strconv.FormatBool(policy.GenerateExistingOnPolicyUpdate),
 	}
 
 	if policy.Background != nil {
 		labels["background"] = strconv.FormatBool(*policy.Background)
 	}
 
+	if policy.WebhookTimeoutSeconds != nil {
+		labels["webhookTimeoutSeconds"] = strconv.Itoa(int(*policy.WebhookTimeoutSeconds))
+	}
+
+	if policy.SchemaValidation != nil {
+		labels["schemaValidation"] = strconv.FormatBool(*policy.SchemaValidation)
+	}
+
 	return labels
 }
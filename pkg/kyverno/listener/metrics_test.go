@@ -52,6 +52,35 @@ func Test_PolicyMetricGeneration(t *testing.T) {
 		}
 	})
 
+	t.Run("Autogen Metric", func(t *testing.T) {
+		pol3 := NewPolicy()
+		autogen := *pol3.Rules[0]
+		autogen.Name = "autogen-" + autogen.Name
+		pol3.Rules[0].Autogen = []*kyverno.Rule{&autogen}
+
+		handler(kyverno.LifecycleEvent{Type: kyverno.Updated, Policy: pol3})
+
+		metricFam, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			t.Errorf("Unexpected Error: %s", err)
+		}
+
+		results := findMetric(metricFam, "kyverno_policy")
+
+		metricResult := results.GetMetric()
+		if len(metricResult) != 2 {
+			t.Fatalf("Expected one metric for the rule and one for the autogen rule, got %d", len(metricResult))
+		}
+		if value := metricLabels(metricResult[0])["rule"]; value != autogen.Name {
+			t.Errorf("Unexpected Rule Label Value: %s", value)
+		}
+		if err = testResultMetricLabels(metricResult[1], pol3); err != nil {
+			t.Error(err)
+		}
+
+		handler(kyverno.LifecycleEvent{Type: kyverno.Deleted, Policy: pol3})
+	})
+
 	t.Run("Deleted Metric", func(t *testing.T) {
 		handler(kyverno.LifecycleEvent{Type: kyverno.Added, Policy: pol1})
 		handler(kyverno.LifecycleEvent{Type: kyverno.Updated, Policy: pol2})
@@ -72,67 +101,33 @@ func Test_PolicyMetricGeneration(t *testing.T) {
 func testResultMetricLabels(metric *io_prometheus_client.Metric, policy kyverno.Policy) error {
 	rule := policy.Rules[0]
 
-	if name := *metric.Label[0].Name; name != "background" {
-		return fmt.Errorf("Unexpected Name Label: %s", name)
-	}
-	if value := *metric.Label[0].Value; value != strconv.FormatBool(*policy.Background) {
-		return fmt.Errorf("Unexpected Background Label Value: %s", value)
-	}
-
-	if name := *metric.Label[1].Name; name != "category" {
-		return fmt.Errorf("Unexpected Name Label: %s", name)
-	}
-	if value := *metric.Label[1].Value; value != policy.Category {
-		return fmt.Errorf("Unexpected Category Label Value: %s", value)
-	}
-
-	if name := *metric.Label[2].Name; name != "kind" {
-		return fmt.Errorf("Unexpected Name Label: %s", name)
-	}
-	if value := *metric.Label[2].Value; value != policy.Kind {
-		return fmt.Errorf("Unexpected Kind Label Value: %s", value)
-	}
-
-	if name := *metric.Label[3].Name; name != "namespace" {
-		return fmt.Errorf("Unexpected Name Label: %s", name)
-	}
-	if value := *metric.Label[3].Value; value != policy.Namespace {
-		return fmt.Errorf("Unexpected Namespace Label Value: %s", value)
-	}
-
-	if name := *metric.Label[4].Name; name != "policy" {
-		return fmt.Errorf("Unexpected Name Label: %s", name)
-	}
-	if value := *metric.Label[4].Value; value != policy.Name {
-		return fmt.Errorf("Unexpected Policy Label Value: %s", value)
-	}
-
-	if name := *metric.Label[5].Name; name != "rule" {
-		return fmt.Errorf("Unexpected Name Label: %s", name)
-	}
-	if value := *metric.Label[5].Value; value != rule.Name {
-		return fmt.Errorf("Unexpected Rule Label Value: %s", value)
-	}
-
-	if name := *metric.Label[6].Name; name != "severity" {
-		return fmt.Errorf("Unexpected Name Label: %s", name)
-	}
-	if value := *metric.Label[6].Value; value != policy.Severity {
-		return fmt.Errorf("Unexpected Severity Label Value: %s", value)
-	}
-
-	if name := *metric.Label[7].Name; name != "type" {
-		return fmt.Errorf("Unexpected Name Label: %s", name)
-	}
-	if value := *metric.Label[7].Value; value != rule.Type {
-		return fmt.Errorf("Unexpected Type Label Value: %s", value)
-	}
-
-	if name := *metric.Label[8].Name; name != "validationFailureAction" {
-		return fmt.Errorf("Unexpected Name Label: %s", name)
-	}
-	if value := *metric.Label[8].Value; value != policy.ValidationFailureAction {
-		return fmt.Errorf("Unexpected ValidationFailureAction Label Value: %s", value)
+	expected := map[string]string{
+		"applyRules":                     policy.ApplyRules,
+		"background":                     strconv.FormatBool(*policy.Background),
+		"category":                       policy.Category,
+		"failurePolicy":                  policy.FailurePolicy,
+		"generateExistingOnPolicyUpdate": strconv.FormatBool(policy.GenerateExistingOnPolicyUpdate),
+		"kind":                           policy.Kind,
+		"mutateExistingOnPolicyUpdate":   strconv.FormatBool(policy.MutateExistingOnPolicyUpdate),
+		"namespace":                      policy.Namespace,
+		"policy":                         policy.Name,
+		"rule":                           rule.Name,
+		"schemaValidation":               strconv.FormatBool(*policy.SchemaValidation),
+		"severity":                       policy.Severity,
+		"type":                           rule.Type,
+		"validationFailureAction":        policy.ValidationFailureAction,
+		"webhookTimeoutSeconds":          strconv.Itoa(int(*policy.WebhookTimeoutSeconds)),
+	}
+
+	labels := metricLabels(metric)
+	if len(labels) != len(expected) {
+		return fmt.Errorf("Unexpected Labels: %v", labels)
+	}
+
+	for name, value := range expected {
+		if labels[name] != value {
+			return fmt.Errorf("Unexpected %s Label Value: %s", name, labels[name])
+		}
 	}
 
 	return nil
@@ -0,0 +1,64 @@
+package listener
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	apiV1 "github.com/kyverno/policy-reporter-kyverno-plugin/pkg/crd/api/kyverno/v1"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+)
+
+// ReasonUnknown is used for Policies without Ready condition which are not ready, e.g. not yet reconciled by Kyverno
+const ReasonUnknown = "Unknown"
+
+// NewPolicyReadyMetricsListener for the readiness of each Policy from its status
+func NewPolicyReadyMetricsListener() kyverno.PolicyListener {
+	readyGauge := promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kyverno_policy_ready",
+		Help: "Readiness of all Policies, 0 if Kyverno failed to apply the Policy or with reason Unknown if the Policy has no status yet",
+	}, []string{"namespace", "kind", "policy", "reason"})
+
+	return func(event kyverno.LifecycleEvent) {
+		policy := event.Policy
+
+		// deleted policies have no kind and the reason can change with each update
+		readyGauge.DeletePartialMatch(prometheus.Labels{"namespace": policy.Namespace, "policy": policy.Name})
+
+		if event.Type == kyverno.Deleted {
+			return
+		}
+
+		ready, reason := readiness(policy)
+
+		value := 0.0
+		if ready {
+			value = 1
+		}
+
+		readyGauge.With(prometheus.Labels{
+			"namespace": policy.Namespace,
+			"kind":      policy.Kind,
+			"policy":    policy.Name,
+			"reason":    reason,
+		}).Set(value)
+	}
+}
+
+// readiness returns the ready state and the reason of the Ready condition
+func readiness(policy kyverno.Policy) (bool, string) {
+	if policy.Status == nil {
+		return false, ReasonUnknown
+	}
+
+	for _, condition := range policy.Status.Conditions {
+		if condition.Type == apiV1.PolicyConditionReady {
+			return policy.Status.Ready, condition.Reason
+		}
+	}
+
+	if !policy.Status.Ready {
+		return false, ReasonUnknown
+	}
+
+	return true, ""
+}
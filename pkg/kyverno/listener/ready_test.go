@@ -0,0 +1,97 @@
+package listener_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno/listener"
+)
+
+func Test_PolicyReadyMetricGeneration(t *testing.T) {
+	handler := listener.NewPolicyReadyMetricsListener()
+
+	policy := kyverno.Policy{Kind: kyverno.ClusterPolicyKind, Name: "require-labels", Status: &kyverno.PolicyStatus{
+		Ready:      false,
+		Conditions: []*kyverno.PolicyCondition{{Type: "Ready", Status: "False", Reason: "Failed", Message: "invalid variable"}},
+	}}
+
+	t.Run("Added Metric", func(t *testing.T) {
+		handler(kyverno.LifecycleEvent{Type: kyverno.Added, Policy: policy})
+
+		metricFam, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		results := findMetric(metricFam, "kyverno_policy_ready")
+		if results == nil || len(results.GetMetric()) != 1 {
+			t.Fatalf("Expected one metric, got %v", results)
+		}
+
+		metric := results.GetMetric()[0]
+		if labels := metricLabels(metric); labels["policy"] != "require-labels" || labels["reason"] != "Failed" || metric.GetGauge().GetValue() != 0 {
+			t.Errorf("Unexpected metric: %v", metric)
+		}
+	})
+
+	t.Run("Updated Metric", func(t *testing.T) {
+		updated := policy
+		updated.Status = &kyverno.PolicyStatus{Ready: true, Conditions: []*kyverno.PolicyCondition{{Type: "Ready", Status: "True", Reason: "Succeeded"}}}
+
+		handler(kyverno.LifecycleEvent{Type: kyverno.Updated, Policy: updated})
+
+		metricFam, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		results := findMetric(metricFam, "kyverno_policy_ready")
+		if results == nil || len(results.GetMetric()) != 1 {
+			t.Fatalf("Expected the previous reason to be replaced, got %v", results)
+		}
+
+		metric := results.GetMetric()[0]
+		if labels := metricLabels(metric); labels["reason"] != "Succeeded" || metric.GetGauge().GetValue() != 1 {
+			t.Errorf("Unexpected metric: %v", metric)
+		}
+	})
+
+	t.Run("Metric without Status", func(t *testing.T) {
+		pending := kyverno.Policy{Kind: kyverno.PolicyKind, Name: "disallow-latest", Namespace: "test"}
+
+		handler(kyverno.LifecycleEvent{Type: kyverno.Added, Policy: pending})
+		defer handler(kyverno.LifecycleEvent{Type: kyverno.Deleted, Policy: pending})
+
+		metricFam, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		results := findMetric(metricFam, "kyverno_policy_ready")
+		for _, metric := range results.GetMetric() {
+			if labels := metricLabels(metric); labels["policy"] == "disallow-latest" {
+				if labels["reason"] != listener.ReasonUnknown || metric.GetGauge().GetValue() != 0 {
+					t.Errorf("Expected reason Unknown for a Policy without status, got %v", metric)
+				}
+				return
+			}
+		}
+
+		t.Error("Expected metric for the Policy without status")
+	})
+
+	t.Run("Deleted Metric", func(t *testing.T) {
+		handler(kyverno.LifecycleEvent{Type: kyverno.Deleted, Policy: kyverno.Policy{Name: "require-labels"}})
+
+		metricFam, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if results := findMetric(metricFam, "kyverno_policy_ready"); results != nil {
+			t.Error("kyverno_policy_ready should no longer exist")
+		}
+	})
+}
@@ -0,0 +1,13 @@
+package listener
+
+import (
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/stream"
+)
+
+// NewStreamListener publishes each Policy kyverno.LifecycleEvent to the given Broker
+func NewStreamListener(broker *stream.Broker[kyverno.LifecycleEvent]) kyverno.PolicyListener {
+	return func(event kyverno.LifecycleEvent) {
+		broker.Publish(event)
+	}
+}
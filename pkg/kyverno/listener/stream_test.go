@@ -0,0 +1,26 @@
+package listener_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno/listener"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/stream"
+)
+
+func Test_StreamListener(t *testing.T) {
+	broker := stream.NewBroker[kyverno.LifecycleEvent](10)
+	subscription, err := broker.Subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscription.Cancel()
+
+	slistener := listener.NewStreamListener(broker)
+	slistener(kyverno.LifecycleEvent{Type: kyverno.Added, Policy: NewPolicy()})
+
+	message := <-subscription.Messages
+	if message.Data.Type != kyverno.Added || message.Data.Policy.Name != "disallow-host-path" {
+		t.Errorf("Unexpected message: %+v", message)
+	}
+}
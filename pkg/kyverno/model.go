@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/segmentio/fasthash/fnv1a"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Event Enum
@@ -28,38 +29,242 @@ type LifecycleEvent struct {
 	Policy Policy
 }
 
+// SecretReference to a Secret with public keys
+type SecretReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// StaticKeyAttestor verifies signatures with public keys
+type StaticKeyAttestor struct {
+	PublicKeys         string           `json:"publicKeys,omitempty"`
+	SignatureAlgorithm string           `json:"signatureAlgorithm,omitempty"`
+	KMS                string           `json:"kms,omitempty"`
+	Secret             *SecretReference `json:"secret,omitempty"`
+	Rekor              string           `json:"rekor,omitempty"`
+}
+
+// CertificateAttestor verifies signatures with certificates
+type CertificateAttestor struct {
+	Certificate      string `json:"cert,omitempty"`
+	CertificateChain string `json:"certChain,omitempty"`
+	Rekor            string `json:"rekor,omitempty"`
+}
+
+// KeylessAttestor verifies keyless signatures of the subject issued by the issuer
+type KeylessAttestor struct {
+	Subject              string            `json:"subject,omitempty"`
+	Issuer               string            `json:"issuer,omitempty"`
+	Roots                string            `json:"roots,omitempty"`
+	Rekor                string            `json:"rekor,omitempty"`
+	AdditionalExtensions map[string]string `json:"additionalExtensions,omitempty"`
+}
+
+// Attestor is a single authority, exactly one of Keys, Certificates, Keyless or the nested Attestor is set
+type Attestor struct {
+	Keys         *StaticKeyAttestor   `json:"keys,omitempty"`
+	Certificates *CertificateAttestor `json:"certificates,omitempty"`
+	Keyless      *KeylessAttestor     `json:"keyless,omitempty"`
+	Attestor     *AttestorSet         `json:"attestor,omitempty"`
+	Annotations  map[string]string    `json:"annotations,omitempty"`
+	Repository   string               `json:"repository,omitempty"`
+}
+
+// AttestorSet requires Count of its Entries to verify, all Entries if Count is nil
+type AttestorSet struct {
+	Count   *int        `json:"count,omitempty"`
+	Entries []*Attestor `json:"entries"`
+}
+
+// Attestation of an in-toto predicate type
+type Attestation struct {
+	PredicateType string         `json:"predicateType"`
+	Attestors     []*AttestorSet `json:"attestors,omitempty"`
+}
+
 // VerifyImage from the Policy spec clusterpolicies.kyverno.io/v1.Policy
 type VerifyImage struct {
-	Attestations string `json:"attestations,omitempty"`
-	Repository   string `json:"repository"`
-	Image        string `json:"image"`
-	Key          string `json:"key"`
+	Repository      string         `json:"repository"`
+	Image           string         `json:"image"`
+	Key             string         `json:"key"`
+	ImageReferences []string       `json:"imageReferences,omitempty"`
+	Attestors       []*AttestorSet `json:"attestors,omitempty"`
+	Attestations    []*Attestation `json:"attestations,omitempty"`
+	MutateDigest    bool           `json:"mutateDigest"`
+	VerifyDigest    bool           `json:"verifyDigest"`
+	Required        bool           `json:"required"`
+}
+
+// Subject of a RoleBinding or ClusterRoleBinding
+type Subject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ResourceDescription selects resources by kind, name, namespace, annotations and labels
+type ResourceDescription struct {
+	Kinds             []string              `json:"kinds,omitempty"`
+	Names             []string              `json:"names,omitempty"`
+	Namespaces        []string              `json:"namespaces,omitempty"`
+	Annotations       map[string]string     `json:"annotations,omitempty"`
+	Selector          *metav1.LabelSelector `json:"selector,omitempty"`
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// ResourceFilter selects resources and the admission request user
+type ResourceFilter struct {
+	Resources    *ResourceDescription `json:"resources,omitempty"`
+	Roles        []string             `json:"roles,omitempty"`
+	ClusterRoles []string             `json:"clusterRoles,omitempty"`
+	Subjects     []Subject            `json:"subjects,omitempty"`
+}
+
+// MatchResources of a match or exclude block, the deprecated top level resources and user info are mapped into All
+type MatchResources struct {
+	Any []*ResourceFilter `json:"any,omitempty"`
+	All []*ResourceFilter `json:"all,omitempty"`
+}
+
+// Condition compares the key with the value, key and value may contain variables
+type Condition struct {
+	Key      any    `json:"key,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	Value    any    `json:"value,omitempty"`
+}
+
+// Conditions of the preconditions of a rule, the deprecated condition list is mapped into All
+type Conditions struct {
+	Any []*Condition `json:"any,omitempty"`
+	All []*Condition `json:"all,omitempty"`
+}
+
+// ConfigMapReference of a context entry
+type ConfigMapReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RequestData is a key value pair of an APICall service request
+type RequestData struct {
+	Key   string `json:"key"`
+	Value any    `json:"value,omitempty"`
+}
+
+// ServiceCall to an external service
+type ServiceCall struct {
+	URL    string        `json:"url"`
+	Method string        `json:"method,omitempty"`
+	Data   []RequestData `json:"data,omitempty"`
+}
+
+// APICall to the Kubernetes API server or an external service
+type APICall struct {
+	URLPath  string       `json:"urlPath,omitempty"`
+	Service  *ServiceCall `json:"service,omitempty"`
+	JMESPath string       `json:"jmesPath,omitempty"`
+}
+
+// ImageRegistry lookup of an image reference
+type ImageRegistry struct {
+	Reference string `json:"reference"`
+	JMESPath  string `json:"jmesPath,omitempty"`
+}
+
+// Variable defined by a value or a JMESPath expression
+type Variable struct {
+	Value    any    `json:"value,omitempty"`
+	JMESPath string `json:"jmesPath,omitempty"`
+	Default  any    `json:"default,omitempty"`
+}
+
+// ContextEntry is an external data source or variable of a rule, Type is the name of the set source
+type ContextEntry struct {
+	Name          string              `json:"name"`
+	Type          string              `json:"type"`
+	ConfigMap     *ConfigMapReference `json:"configMap,omitempty"`
+	APICall       *APICall            `json:"apiCall,omitempty"`
+	ImageRegistry *ImageRegistry      `json:"imageRegistry,omitempty"`
+	Variable      *Variable           `json:"variable,omitempty"`
+}
+
+// ImageExtractor defines where images are found in custom resources
+type ImageExtractor struct {
+	Path  string `json:"path"`
+	Value string `json:"value,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Key   string `json:"key,omitempty"`
 }
 
 // Rule from the Policy spec clusterpolicies.kyverno.io/v1.Policy
 type Rule struct {
-	ValidateMessage string         `json:"message,omitempty"`
-	Name            string         `json:"name"`
-	Type            string         `json:"type"`
-	VerifyImages    []*VerifyImage `json:"verifyImages,omitempty"`
+	ValidateMessage string                       `json:"message,omitempty"`
+	Name            string                       `json:"name"`
+	Type            string                       `json:"type"`
+	VerifyImages    []*VerifyImage               `json:"verifyImages,omitempty"`
+	Match           *MatchResources              `json:"match,omitempty"`
+	Exclude         *MatchResources              `json:"exclude,omitempty"`
+	Preconditions   *Conditions                  `json:"preconditions,omitempty"`
+	Context         []*ContextEntry              `json:"context,omitempty"`
+	ImageExtractors map[string][]*ImageExtractor `json:"imageExtractors,omitempty"`
+	Autogen         []*Rule                      `json:"autogen,omitempty"`
+}
+
+// ValidationFailureActionOverride changes the action for namespaces matching the names and the selector
+type ValidationFailureActionOverride struct {
+	Action            string                `json:"action"`
+	Namespaces        []string              `json:"namespaces,omitempty"`
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// PolicyCondition from the Policy status
+type PolicyCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// RuleCount per rule type from the Policy status
+type RuleCount struct {
+	Validate     int `json:"validate"`
+	Generate     int `json:"generate"`
+	Mutate       int `json:"mutate"`
+	VerifyImages int `json:"verifyImages"`
+}
+
+// PolicyStatus with the readiness of the Policy, Ready is taken from the Ready condition if present
+type PolicyStatus struct {
+	Ready      bool               `json:"ready"`
+	Conditions []*PolicyCondition `json:"conditions,omitempty"`
+	RuleCount  RuleCount          `json:"ruleCount"`
 }
 
 // Policy spec clusterpolicies.kyverno.io/v1.Policy
 type Policy struct {
-	Kind                    string    `json:"kind"`
-	APIVersion              string    `json:"apiVersion"`
-	Name                    string    `json:"name"`
-	Namespace               string    `json:"namespace,omitempty"`
-	AutogenControllers      []string  `json:"autogenControllers,omitempty"`
-	ValidationFailureAction string    `json:"validationFailureAction,omitempty"`
-	Background              *bool     `json:"background"`
-	Rules                   []*Rule   `json:"rules"`
-	Category                string    `json:"category,omitempty"`
-	Description             string    `json:"description,omitempty"`
-	Severity                string    `json:"severity,omitempty"`
-	CreationTimestamp       time.Time `json:"creationTimestamp,omitempty"`
-	UID                     string    `json:"uid,omitempty"`
-	Content                 string    `json:"content"`
+	Kind                             string                             `json:"kind"`
+	APIVersion                       string                             `json:"apiVersion"`
+	Name                             string                             `json:"name"`
+	Namespace                        string                             `json:"namespace,omitempty"`
+	AutogenControllers               []string                           `json:"autogenControllers,omitempty"`
+	ValidationFailureAction          string                             `json:"validationFailureAction,omitempty"`
+	ValidationFailureActionOverrides []*ValidationFailureActionOverride `json:"validationFailureActionOverrides,omitempty"`
+	Background                       *bool                              `json:"background"`
+	FailurePolicy                    string                             `json:"failurePolicy,omitempty"`
+	WebhookTimeoutSeconds            *int32                             `json:"webhookTimeoutSeconds,omitempty"`
+	ApplyRules                       string                             `json:"applyRules,omitempty"`
+	SchemaValidation                 *bool                              `json:"schemaValidation,omitempty"`
+	MutateExistingOnPolicyUpdate     bool                               `json:"mutateExistingOnPolicyUpdate,omitempty"`
+	GenerateExistingOnPolicyUpdate   bool                               `json:"generateExistingOnPolicyUpdate,omitempty"`
+	Rules                            []*Rule                            `json:"rules"`
+	Category                         string                             `json:"category,omitempty"`
+	Description                      string                             `json:"description,omitempty"`
+	Severity                         string                             `json:"severity,omitempty"`
+	CreationTimestamp                time.Time                          `json:"creationTimestamp,omitempty"`
+	UID                              string                             `json:"uid,omitempty"`
+	Content                          string                             `json:"content"`
+	Status                           *PolicyStatus                      `json:"status,omitempty"`
 }
 
 func (p *Policy) GetID() string {
@@ -6,8 +6,9 @@ import (
 
 // PolicyStore persists the last state of a Policy in memory
 type PolicyStore struct {
-	store map[string]Policy
-	rwm   *sync.RWMutex
+	store    map[string]Policy
+	rwm      *sync.RWMutex
+	revision uint64
 }
 
 // Get a Policy from the Store by ID
@@ -36,16 +37,28 @@ func (s *PolicyStore) List() []Policy {
 func (s *PolicyStore) Add(r Policy) {
 	s.rwm.Lock()
 	s.store[r.GetID()] = r
+	s.revision++
 	s.rwm.Unlock()
 }
 
 // Remove a Policy to the store
 func (s *PolicyStore) Remove(id string) {
 	s.rwm.Lock()
-	delete(s.store, id)
+	if _, ok := s.store[id]; ok {
+		delete(s.store, id)
+		s.revision++
+	}
 	s.rwm.Unlock()
 }
 
+// Revision is increased with each change of the stored Policies
+func (s *PolicyStore) Revision() uint64 {
+	s.rwm.RLock()
+	defer s.rwm.RUnlock()
+
+	return s.revision
+}
+
 // NewPolicyStore returns a pointer to a new in memory store
 func NewPolicyStore() *PolicyStore {
 	return &PolicyStore{
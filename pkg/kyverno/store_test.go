@@ -58,3 +58,26 @@ func Test_PolicyStore(t *testing.T) {
 		}
 	})
 }
+
+func Test_PolicyStoreRevision(t *testing.T) {
+	store := kyverno.NewPolicyStore()
+	pol := NewPolicy()
+
+	revision := store.Revision()
+
+	store.Add(pol)
+	if store.Revision() <= revision {
+		t.Fatalf("Revision should increase after Add")
+	}
+	revision = store.Revision()
+
+	store.Remove("not-exist")
+	if store.Revision() != revision {
+		t.Errorf("Revision should not change after removing an unknown Policy")
+	}
+
+	store.Remove(pol.GetID())
+	if store.Revision() <= revision {
+		t.Errorf("Revision should increase after Remove")
+	}
+}
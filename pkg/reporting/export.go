@@ -0,0 +1,112 @@
+package reporting
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+)
+
+// Columns of the tabular report exports
+var Columns = []string{"Namespace", "Policy", "Title", "Category", "Severity", "Rule", "Kind", "APIVersion", "Resource", "Status"}
+
+// Row is a single resource result of a tabular report export
+type Row struct {
+	Namespace  string
+	Policy     string
+	Title      string
+	Category   string
+	Severity   string
+	Rule       string
+	Kind       string
+	APIVersion string
+	Resource   string
+	Status     string
+}
+
+func (r Row) values() []string {
+	return []string{r.Namespace, r.Policy, r.Title, r.Category, r.Severity, r.Rule, r.Kind, r.APIVersion, r.Resource, r.Status}
+}
+
+// PolicyRows flattens the result of PerPolicyData into one row per namespace, policy, rule and resource
+func PolicyRows(data []*Validation) []Row {
+	rows := make([]Row, 0)
+
+	for _, validation := range data {
+		for _, namespace := range sortedKeys(validation.Groups) {
+			rows = appendRuleRows(rows, namespace, validation.Name, validation.Policy, validation.Groups[namespace].Rules)
+		}
+	}
+
+	return rows
+}
+
+// NamespaceRows flattens the result of PerNamespaceData into one row per namespace, policy, rule and resource
+func NamespaceRows(data []*Validation) []Row {
+	rows := make([]Row, 0)
+
+	for _, validation := range data {
+		for _, policy := range sortedKeys(validation.Groups) {
+			group := validation.Groups[policy]
+
+			rows = appendRuleRows(rows, validation.Name, policy, group.Policy, group.Rules)
+		}
+	}
+
+	return rows
+}
+
+// WriteCSV writes the rows including a header line as CSV
+func WriteCSV(w io.Writer, rows []Row) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(Columns); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := writer.Write(row.values()); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func appendRuleRows(rows []Row, namespace, policy string, details *Policy, rules map[string]*Rule) []Row {
+	for _, name := range sortedKeys(rules) {
+		for _, resource := range rules[name].Resources {
+			row := Row{
+				Namespace:  namespace,
+				Policy:     policy,
+				Rule:       name,
+				Kind:       resource.Kind,
+				APIVersion: resource.APIVersion,
+				Resource:   resource.Name,
+				Status:     resource.Status,
+			}
+
+			if details != nil {
+				row.Title = details.Title
+				row.Category = details.Category
+				row.Severity = details.Severity
+			}
+
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
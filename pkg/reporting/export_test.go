@@ -0,0 +1,166 @@
+package reporting_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting"
+)
+
+var policyData = []*reporting.Validation{
+	{
+		Name: "disallow-capabilities",
+		Policy: &reporting.Policy{
+			Title:    "Disallow Capabilities",
+			Category: "Pod Security Standards (Baseline)",
+			Severity: "medium",
+		},
+		Groups: map[string]*reporting.Group{
+			"kyverno": {
+				Rules: map[string]*reporting.Rule{
+					"adding-capabilities": {
+						Summary: &reporting.Summary{Pass: 1, Fail: 1},
+						Resources: []*reporting.Resource{
+							{Name: "kyverno", Kind: "Deployment", APIVersion: "apps/v1", Status: "pass"},
+							{Name: "nginx", Kind: "Pod", APIVersion: "v1", Status: "fail"},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+var namespaceData = []*reporting.Validation{
+	{
+		Name: "kyverno",
+		Groups: map[string]*reporting.Group{
+			"disallow-capabilities": {
+				Name:   "disallow-capabilities",
+				Policy: &reporting.Policy{Title: "Disallow Capabilities", Category: "Pod Security Standards (Baseline)", Severity: "medium"},
+				Rules: map[string]*reporting.Rule{
+					"adding-capabilities": {
+						Summary:   &reporting.Summary{Pass: 1},
+						Resources: []*reporting.Resource{{Name: "kyverno", Kind: "Deployment", APIVersion: "apps/v1", Status: "pass"}},
+					},
+				},
+			},
+		},
+	},
+}
+
+func Test_Rows(t *testing.T) {
+	t.Run("PolicyRows", func(t *testing.T) {
+		rows := reporting.PolicyRows(policyData)
+		if len(rows) != 2 {
+			t.Fatalf("expected 2 rows, got %d", len(rows))
+		}
+
+		expected := reporting.Row{
+			Namespace:  "kyverno",
+			Policy:     "disallow-capabilities",
+			Title:      "Disallow Capabilities",
+			Category:   "Pod Security Standards (Baseline)",
+			Severity:   "medium",
+			Rule:       "adding-capabilities",
+			Kind:       "Pod",
+			APIVersion: "v1",
+			Resource:   "nginx",
+			Status:     "fail",
+		}
+		if rows[1] != expected {
+			t.Errorf("unexpected row: %+v", rows[1])
+		}
+	})
+
+	t.Run("NamespaceRows", func(t *testing.T) {
+		rows := reporting.NamespaceRows(namespaceData)
+		if len(rows) != 1 {
+			t.Fatalf("expected 1 row, got %d", len(rows))
+		}
+
+		if rows[0].Namespace != "kyverno" || rows[0].Policy != "disallow-capabilities" || rows[0].Severity != "medium" {
+			t.Errorf("unexpected row: %+v", rows[0])
+		}
+	})
+}
+
+func Test_WriteCSV(t *testing.T) {
+	buffer := &bytes.Buffer{}
+
+	if err := reporting.WriteCSV(buffer, reporting.PolicyRows(policyData)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Namespace,Policy,Title,Category,Severity,Rule,Kind,APIVersion,Resource,Status\n" +
+		"kyverno,disallow-capabilities,Disallow Capabilities,Pod Security Standards (Baseline),medium,adding-capabilities,Deployment,apps/v1,kyverno,pass\n" +
+		"kyverno,disallow-capabilities,Disallow Capabilities,Pod Security Standards (Baseline),medium,adding-capabilities,Pod,v1,nginx,fail\n"
+
+	if buffer.String() != expected {
+		t.Errorf("unexpected csv: %s", buffer.String())
+	}
+}
+
+func Test_WriteXLSX(t *testing.T) {
+	buffer := &bytes.Buffer{}
+
+	if err := reporting.WriteXLSX(buffer, reporting.PolicyRows(policyData)); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sheet string
+	for _, file := range archive.File {
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, _ := io.ReadAll(content)
+		sheet = string(b)
+	}
+
+	if !strings.Contains(sheet, `<c r="J3" t="inlineStr"><is><t>fail</t></is></c>`) {
+		t.Errorf("unexpected sheet content: %s", sheet)
+	}
+	if !strings.Contains(sheet, `<t>Pod Security Standards (Baseline)</t>`) {
+		t.Errorf("expected category in sheet content: %s", sheet)
+	}
+}
+
+func Test_WritePDF(t *testing.T) {
+	t.Run("PolicyPDF", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+
+		if err := reporting.WritePolicyPDF(buffer, policyData); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.HasPrefix(buffer.Bytes(), []byte("%PDF-")) {
+			t.Errorf("expected PDF document")
+		}
+	})
+
+	t.Run("NamespacePDF", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+
+		if err := reporting.WriteNamespacePDF(buffer, namespaceData); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.HasPrefix(buffer.Bytes(), []byte("%PDF-")) {
+			t.Errorf("expected PDF document")
+		}
+	})
+}
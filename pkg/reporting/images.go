@@ -0,0 +1,109 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting/kubernetes"
+)
+
+const (
+	ImageSourcePods      = "pods"
+	ImageSourceWorkloads = "workloads"
+)
+
+// ImageUsage is a container using an image
+type ImageUsage struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Container string `json:"container"`
+}
+
+// NamespaceImages are all images used in a namespace with their containers
+type NamespaceImages struct {
+	Name   string
+	Images map[string][]ImageUsage
+}
+
+type ImageGenerator interface {
+	NamespaceImages(ctx context.Context, source string, filter Filter) ([]*NamespaceImages, error)
+}
+
+type imageGenerator struct {
+	client *kubernetes.WorkloadClient
+}
+
+// NamespaceImages collects the container images of running Pods or of Pod controllers per namespace
+func (g *imageGenerator) NamespaceImages(ctx context.Context, source string, filter Filter) ([]*NamespaceImages, error) {
+	var workloads []kubernetes.Workload
+	var err error
+
+	switch source {
+	case ImageSourcePods:
+		workloads, err = g.client.RunningPods(ctx)
+	case ImageSourceWorkloads:
+		workloads, err = g.client.Controllers(ctx)
+	default:
+		return nil, fmt.Errorf("unknown image source %q", source)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return GroupImages(workloads, filter), nil
+}
+
+// GroupImages groups the container images of the workloads by namespace
+func GroupImages(workloads []kubernetes.Workload, filter Filter) []*NamespaceImages {
+	mapping := make(map[string]*NamespaceImages)
+
+	for _, workload := range workloads {
+		if !filter.IncludesNamespace(workload.Namespace) {
+			continue
+		}
+
+		ns, ok := mapping[workload.Namespace]
+		if !ok {
+			ns = &NamespaceImages{Name: workload.Namespace, Images: make(map[string][]ImageUsage)}
+			mapping[workload.Namespace] = ns
+		}
+
+		for _, container := range containers(workload.Spec) {
+			ns.Images[container.Image] = append(ns.Images[container.Image], ImageUsage{
+				Kind:      workload.Kind,
+				Name:      workload.Name,
+				Container: container.Name,
+			})
+		}
+	}
+
+	list := make([]*NamespaceImages, 0, len(mapping))
+	for _, ns := range mapping {
+		list = append(list, ns)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list
+}
+
+// containers returns the init, app and ephemeral containers with their image
+func containers(spec corev1.PodSpec) []corev1.Container {
+	list := make([]corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers)+len(spec.EphemeralContainers))
+	list = append(list, spec.InitContainers...)
+	list = append(list, spec.Containers...)
+
+	for _, container := range spec.EphemeralContainers {
+		list = append(list, corev1.Container{Name: container.Name, Image: container.Image})
+	}
+
+	return list
+}
+
+func NewImageGenerator(client *kubernetes.WorkloadClient) ImageGenerator {
+	return &imageGenerator{client}
+}
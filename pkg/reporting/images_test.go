@@ -0,0 +1,116 @@
+package reporting_test
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting/kubernetes"
+)
+
+func newWorkloadClient() *kubernetes.WorkloadClient {
+	return kubernetes.NewWorkloadClient(fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				InitContainers:      []corev1.Container{{Name: "init", Image: "busybox"}},
+				Containers:          []corev1.Container{{Name: "app", Image: "ghcr.io/org/app:1.2"}},
+				EphemeralContainers: []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debug", Image: "busybox"}}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "done", Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "job", Image: "alpine"}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "api", Image: "nginx"}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "web", Image: "nginx"}},
+			}}},
+		},
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default"},
+			Spec: batchv1.CronJobSpec{JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "backup", Image: "alpine"}},
+			}}}}},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "default", OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "backup"}}},
+			Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "backup", Image: "alpine"}},
+			}}},
+		},
+	))
+}
+
+func Test_NamespaceImages(t *testing.T) {
+	generator := reporting.NewImageGenerator(newWorkloadClient())
+
+	t.Run("running pods", func(t *testing.T) {
+		namespaces, err := generator.NamespaceImages(context.Background(), reporting.ImageSourcePods, reporting.Filter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(namespaces) != 2 || namespaces[0].Name != "default" || namespaces[1].Name != "team" {
+			t.Fatalf("Expected sorted namespaces default and team, got %+v", namespaces)
+		}
+
+		images := namespaces[0].Images
+		if len(images) != 2 || len(images["busybox"]) != 2 || len(images["ghcr.io/org/app:1.2"]) != 1 {
+			t.Errorf("Expected init, app and ephemeral container images of the running pod, got %+v", images)
+		}
+		if usage := images["ghcr.io/org/app:1.2"][0]; usage.Kind != "Pod" || usage.Name != "app" || usage.Container != "app" {
+			t.Errorf("Unexpected image usage: %+v", usage)
+		}
+	})
+
+	t.Run("workloads", func(t *testing.T) {
+		namespaces, err := generator.NamespaceImages(context.Background(), reporting.ImageSourceWorkloads, reporting.Filter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(namespaces) != 2 {
+			t.Fatalf("Expected 2 namespaces, got %d", len(namespaces))
+		}
+
+		backup := namespaces[0].Images["alpine"]
+		if len(backup) != 1 || backup[0].Kind != "CronJob" {
+			t.Errorf("Expected only the CronJob for jobs it owns, got %+v", backup)
+		}
+		if web := namespaces[1].Images["nginx"]; len(web) != 1 || web[0].Kind != "Deployment" {
+			t.Errorf("Expected the Deployment image, got %+v", web)
+		}
+	})
+
+	t.Run("namespace filter", func(t *testing.T) {
+		namespaces, err := generator.NamespaceImages(context.Background(), reporting.ImageSourcePods, reporting.Filter{Namespaces: []string{"team"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(namespaces) != 1 || namespaces[0].Name != "team" {
+			t.Errorf("Expected only the team namespace, got %+v", namespaces)
+		}
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		if _, err := generator.NamespaceImages(context.Background(), "nodes", reporting.Filter{}); err == nil {
+			t.Error("Expected error for unknown source")
+		}
+	})
+}
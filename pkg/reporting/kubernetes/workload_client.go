@@ -0,0 +1,144 @@
+package kubernetes
+
+import (
+	"context"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/pager"
+)
+
+// workloadCacheTTL keeps the listed workloads for subsequent report requests
+const workloadCacheTTL = 30 * time.Second
+
+const (
+	podsCacheKey        = "pods"
+	controllersCacheKey = "controllers"
+)
+
+// Workload is a Pod or a Pod controller with its Pod template
+type Workload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Spec      corev1.PodSpec
+}
+
+// WorkloadClient lists workloads page by page and caches the result for a short time,
+// so repeated report requests do not list all Pods of the cluster again
+type WorkloadClient struct {
+	client k8s.Interface
+	cache  *gocache.Cache
+}
+
+func NewWorkloadClient(client k8s.Interface) *WorkloadClient {
+	return &WorkloadClient{client: client, cache: gocache.New(workloadCacheTTL, 2*workloadCacheTTL)}
+}
+
+// RunningPods returns all Pods in the Running phase
+func (c *WorkloadClient) RunningPods(ctx context.Context) ([]Workload, error) {
+	if workloads, ok := c.cache.Get(podsCacheKey); ok {
+		return workloads.([]Workload), nil
+	}
+
+	workloads := make([]Workload, 0)
+
+	pods := pager.New(func(ctx context.Context, opts v1.ListOptions) (runtime.Object, error) {
+		return c.client.CoreV1().Pods("").List(ctx, opts)
+	})
+
+	err := pods.EachListItem(ctx, v1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("status.phase", string(corev1.PodRunning)).String(),
+	}, func(obj runtime.Object) error {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Status.Phase != corev1.PodRunning {
+			return nil
+		}
+
+		workloads = append(workloads, Workload{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, Spec: pod.Spec})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.SetDefault(podsCacheKey, workloads)
+
+	return workloads, nil
+}
+
+// Controllers returns all Deployments, StatefulSets, DaemonSets, Jobs and CronJobs
+func (c *WorkloadClient) Controllers(ctx context.Context) ([]Workload, error) {
+	if workloads, ok := c.cache.Get(controllersCacheKey); ok {
+		return workloads.([]Workload), nil
+	}
+
+	workloads := make([]Workload, 0)
+
+	lists := []pager.ListPageFunc{
+		func(ctx context.Context, opts v1.ListOptions) (runtime.Object, error) {
+			return c.client.AppsV1().Deployments("").List(ctx, opts)
+		},
+		func(ctx context.Context, opts v1.ListOptions) (runtime.Object, error) {
+			return c.client.AppsV1().StatefulSets("").List(ctx, opts)
+		},
+		func(ctx context.Context, opts v1.ListOptions) (runtime.Object, error) {
+			return c.client.AppsV1().DaemonSets("").List(ctx, opts)
+		},
+		func(ctx context.Context, opts v1.ListOptions) (runtime.Object, error) {
+			return c.client.BatchV1().Jobs("").List(ctx, opts)
+		},
+		func(ctx context.Context, opts v1.ListOptions) (runtime.Object, error) {
+			return c.client.BatchV1().CronJobs("").List(ctx, opts)
+		},
+	}
+
+	for _, list := range lists {
+		err := pager.New(list).EachListItem(ctx, v1.ListOptions{}, func(obj runtime.Object) error {
+			if workload, ok := controllerWorkload(obj); ok {
+				workloads = append(workloads, workload)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.cache.SetDefault(controllersCacheKey, workloads)
+
+	return workloads, nil
+}
+
+func controllerWorkload(obj runtime.Object) (Workload, bool) {
+	switch item := obj.(type) {
+	case *appsv1.Deployment:
+		return Workload{Kind: "Deployment", Namespace: item.Namespace, Name: item.Name, Spec: item.Spec.Template.Spec}, true
+	case *appsv1.StatefulSet:
+		return Workload{Kind: "StatefulSet", Namespace: item.Namespace, Name: item.Name, Spec: item.Spec.Template.Spec}, true
+	case *appsv1.DaemonSet:
+		return Workload{Kind: "DaemonSet", Namespace: item.Namespace, Name: item.Name, Spec: item.Spec.Template.Spec}, true
+	case *batchv1.Job:
+		// Jobs created by a CronJob are covered by their CronJob
+		for _, owner := range item.OwnerReferences {
+			if owner.Kind == "CronJob" {
+				return Workload{}, false
+			}
+		}
+
+		return Workload{Kind: "Job", Namespace: item.Namespace, Name: item.Name, Spec: item.Spec.Template.Spec}, true
+	case *batchv1.CronJob:
+		return Workload{Kind: "CronJob", Namespace: item.Namespace, Name: item.Name, Spec: item.Spec.JobTemplate.Spec.Template.Spec}, true
+	}
+
+	return Workload{}, false
+}
@@ -0,0 +1,54 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting/kubernetes"
+)
+
+func Test_WorkloadClientControllers(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team"}},
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default"}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "default", OwnerReferences: []metav1.OwnerReference{
+			{Kind: "ConfigMap", Name: "backup-config"},
+			{Kind: "CronJob", Name: "backup", Controller: &[]bool{true}[0]},
+		}}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: "team"}},
+	)
+
+	workloadClient := kubernetes.NewWorkloadClient(client)
+
+	workloads, err := workloadClient.Controllers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make(map[string]string, len(workloads))
+	for _, workload := range workloads {
+		names[workload.Name] = workload.Kind
+	}
+
+	if len(names) != 3 || names["web"] != "Deployment" || names["backup"] != "CronJob" || names["migration"] != "Job" {
+		t.Errorf("Expected all controllers except Jobs owned by a CronJob, got %+v", names)
+	}
+
+	if err := client.AppsV1().Deployments("team").Delete(context.Background(), "web", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	cached, err := workloadClient.Controllers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cached) != len(workloads) {
+		t.Errorf("Expected cached controllers, got %d", len(cached))
+	}
+}
@@ -3,42 +3,42 @@ package reporting
 import "strings"
 
 type Summary struct {
-	Error   int
-	Pass    int
-	Fail    int
-	Warning int
+	Error   int `json:"error"`
+	Pass    int `json:"pass"`
+	Fail    int `json:"fail"`
+	Warning int `json:"warning"`
 }
 
 type Resource struct {
-	Kind       string
-	APIVersion string
-	Name       string
-	Status     string
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
 }
 
 type Rule struct {
-	Summary   *Summary
-	Resources []*Resource
+	Summary   *Summary    `json:"summary"`
+	Resources []*Resource `json:"resources"`
 }
 
 type Group struct {
-	Name    string
-	Policy  *Policy
-	Summary *Summary
-	Rules   map[string]*Rule
+	Name    string           `json:"name"`
+	Policy  *Policy          `json:"policy,omitempty"`
+	Summary *Summary         `json:"summary"`
+	Rules   map[string]*Rule `json:"rules"`
 }
 
 type Policy struct {
-	Title       string
-	Category    string
-	Description string
-	Severity    string
+	Title       string `json:"title"`
+	Category    string `json:"category"`
+	Description string `json:"description,omitempty"`
+	Severity    string `json:"severity,omitempty"`
 }
 
 type Validation struct {
-	Name   string
-	Policy *Policy
-	Groups map[string]*Group
+	Name   string            `json:"name"`
+	Policy *Policy           `json:"policy,omitempty"`
+	Groups map[string]*Group `json:"groups"`
 }
 
 type Filter struct {
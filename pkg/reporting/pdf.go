@@ -0,0 +1,311 @@
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pdfMargin     = 10.0
+	pdfLineHeight = 6.0
+)
+
+var statusColors = map[string][3]int{
+	"pass":    {25, 135, 84},
+	"warning": {253, 126, 20},
+	"warn":    {253, 126, 20},
+	"fail":    {220, 53, 69},
+	"error":   {137, 30, 41},
+}
+
+type pdfColumn struct {
+	Header string
+	Width  float64
+	Align  string
+	// Status colors every cell of the column with the given status color
+	Status string
+	// StatusValue colors every cell by its own value
+	StatusValue bool
+}
+
+type pdfReport struct {
+	pdf       *fpdf.Fpdf
+	translate func(string) string
+}
+
+// WritePolicyPDF renders the result of PerPolicyData as PDF, grouped by policy category
+func WritePolicyPDF(w io.Writer, data []*Validation) error {
+	r := newPDFReport("Kyverno Policy Report")
+
+	category := ""
+	for i, validation := range data {
+		if i > 0 {
+			r.pdf.AddPage()
+		}
+
+		if i == 0 || category != validation.Policy.Category {
+			category = validation.Policy.Category
+			r.heading(category, 16)
+		}
+
+		r.policyHeader(validation.Name, validation.Policy)
+		r.subheading("Summary")
+
+		rows := make([][]string, 0)
+		total := &Summary{}
+		for _, namespace := range sortedKeys(validation.Groups) {
+			for _, rule := range sortedKeys(validation.Groups[namespace].Rules) {
+				summary := validation.Groups[namespace].Rules[rule].Summary
+				rows = append(rows, append([]string{clusterScoped(namespace), rule}, summaryValues(summary)...))
+				addSummary(total, summary)
+			}
+		}
+
+		r.table(summaryColumns("Namespace"), rows, append([]string{"Summary", ""}, summaryValues(total)...))
+
+		r.subheading("Details per Namespace")
+		for _, namespace := range sortedKeys(validation.Groups) {
+			group := validation.Groups[namespace]
+
+			r.heading("Namespace: "+clusterScoped(namespace), 11)
+			r.table(summaryColumns("")[2:], [][]string{summaryValues(group.Summary)}, nil)
+			r.resourceTable(group.Rules)
+		}
+	}
+
+	return r.output(w)
+}
+
+// WriteNamespacePDF renders the result of PerNamespaceData as PDF, grouped by namespace
+func WriteNamespacePDF(w io.Writer, data []*Validation) error {
+	r := newPDFReport("Kyverno Policy Namespace Report")
+
+	for i, validation := range data {
+		if i > 0 {
+			r.pdf.AddPage()
+		}
+
+		r.heading("Namespace: "+clusterScoped(validation.Name), 16)
+		r.subheading("Summary")
+
+		rows := make([][]string, 0)
+		total := &Summary{}
+		for _, policy := range sortedKeys(validation.Groups) {
+			group := validation.Groups[policy]
+			for _, rule := range sortedKeys(group.Rules) {
+				summary := group.Rules[rule].Summary
+				rows = append(rows, append([]string{policyTitle(policy, group.Policy), rule}, summaryValues(summary)...))
+				addSummary(total, summary)
+			}
+		}
+
+		r.table(summaryColumns("Policy"), rows, append([]string{"Summary", ""}, summaryValues(total)...))
+
+		r.subheading("Details per Policy")
+		for _, policy := range sortedKeys(validation.Groups) {
+			group := validation.Groups[policy]
+
+			r.policyHeader(policy, group.Policy)
+			r.table(summaryColumns("")[2:], [][]string{summaryValues(group.Summary)}, nil)
+			r.resourceTable(group.Rules)
+		}
+	}
+
+	return r.output(w)
+}
+
+func newPDFReport(title string) *pdfReport {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.SetTitle(title, true)
+
+	r := &pdfReport{pdf: pdf, translate: pdf.UnicodeTranslatorFromDescriptor("")}
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%s - Page %d/{nb}", r.translate(title), pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	r.heading(title, 22)
+
+	return r
+}
+
+func (r *pdfReport) heading(text string, size float64) {
+	r.pdf.SetFont("Helvetica", "B", size)
+	r.pdf.SetTextColor(0, 0, 0)
+	r.pdf.MultiCell(0, size*0.5, r.translate(text), "", "L", false)
+	r.pdf.Ln(2)
+}
+
+func (r *pdfReport) subheading(text string) {
+	r.pdf.Ln(2)
+	r.heading(text, 13)
+}
+
+func (r *pdfReport) policyHeader(name string, policy *Policy) {
+	r.heading(policyTitle(name, policy), 14)
+
+	if policy == nil {
+		return
+	}
+
+	r.pdf.SetFont("Helvetica", "", 10)
+	r.pdf.MultiCell(0, 5, r.translate(fmt.Sprintf("%s | %s | Severity: %s", name, policy.Category, policy.Severity)), "", "L", false)
+
+	if policy.Description != "" {
+		r.pdf.Ln(1)
+		r.pdf.SetFont("Helvetica", "I", 9)
+		r.pdf.MultiCell(0, 4.5, r.translate(policy.Description), "", "L", false)
+	}
+
+	r.pdf.Ln(2)
+}
+
+func (r *pdfReport) resourceTable(rules map[string]*Rule) {
+	rows := make([][]string, 0)
+	for _, rule := range sortedKeys(rules) {
+		for _, resource := range rules[rule].Resources {
+			rows = append(rows, []string{resource.APIVersion, resource.Kind, resource.Name, rule, resource.Status})
+		}
+	}
+
+	r.table([]pdfColumn{
+		{Header: "APIVersion", Width: 30, Align: "L"},
+		{Header: "Kind", Width: 30, Align: "L"},
+		{Header: "Name", Width: 55, Align: "L"},
+		{Header: "Rule", Width: 55, Align: "L"},
+		{Header: "Result", Width: 20, Align: "C", StatusValue: true},
+	}, rows, nil)
+}
+
+// table renders the rows and repeats the header row on each new page
+func (r *pdfReport) table(columns []pdfColumn, rows [][]string, footer []string) {
+	_, pageHeight := r.pdf.GetPageSize()
+	_, _, _, bottom := r.pdf.GetMargins()
+
+	header := func() {
+		r.pdf.SetFont("Helvetica", "B", 9)
+		r.pdf.SetFillColor(238, 238, 238)
+		r.pdf.SetTextColor(0, 0, 0)
+		for _, column := range columns {
+			r.pdf.CellFormat(column.Width, pdfLineHeight, column.Header, "1", 0, column.Align, true, 0, "")
+		}
+		r.pdf.Ln(-1)
+	}
+
+	row := func(values []string, style string) {
+		if r.pdf.GetY()+pdfLineHeight > pageHeight-bottom {
+			r.pdf.AddPage()
+			header()
+		}
+
+		r.pdf.SetFont("Helvetica", style, 8)
+		for i, column := range columns {
+			value := ""
+			if i < len(values) {
+				value = values[i]
+			}
+
+			fill := false
+			r.pdf.SetTextColor(0, 0, 0)
+
+			status := column.Status
+			if column.StatusValue {
+				status = value
+			}
+
+			if color, ok := statusColors[status]; ok {
+				fill = true
+				r.pdf.SetFillColor(color[0], color[1], color[2])
+				r.pdf.SetTextColor(255, 255, 255)
+			}
+
+			r.pdf.CellFormat(column.Width, pdfLineHeight, r.fit(value, column.Width), "1", 0, column.Align, fill, 0, "")
+		}
+		r.pdf.Ln(-1)
+	}
+
+	header()
+	for _, values := range rows {
+		row(values, "")
+	}
+
+	if footer != nil {
+		row(footer, "B")
+	}
+
+	r.pdf.Ln(4)
+}
+
+// fit shortens the text to the available cell width
+func (r *pdfReport) fit(text string, width float64) string {
+	text = r.translate(text)
+	if r.pdf.GetStringWidth(text) <= width-2 {
+		return text
+	}
+
+	for len(text) > 0 && r.pdf.GetStringWidth(text+"...") > width-2 {
+		text = text[:len(text)-1]
+	}
+
+	return text + "..."
+}
+
+func (r *pdfReport) output(w io.Writer) error {
+	return r.pdf.Output(w)
+}
+
+func summaryColumns(group string) []pdfColumn {
+	return []pdfColumn{
+		{Header: group, Width: 55, Align: "L"},
+		{Header: "Rule", Width: 55, Align: "L"},
+		{Header: "Pass", Width: 20, Align: "R", Status: "pass"},
+		{Header: "Warning", Width: 20, Align: "R", Status: "warning"},
+		{Header: "Fail", Width: 20, Align: "R", Status: "fail"},
+		{Header: "Error", Width: 20, Align: "R", Status: "error"},
+	}
+}
+
+func summaryValues(summary *Summary) []string {
+	if summary == nil {
+		summary = &Summary{}
+	}
+
+	return []string{strconv.Itoa(summary.Pass), strconv.Itoa(summary.Warning), strconv.Itoa(summary.Fail), strconv.Itoa(summary.Error)}
+}
+
+func addSummary(total, summary *Summary) {
+	if summary == nil {
+		return
+	}
+
+	total.Pass += summary.Pass
+	total.Warning += summary.Warning
+	total.Fail += summary.Fail
+	total.Error += summary.Error
+}
+
+func clusterScoped(namespace string) string {
+	if namespace == "" {
+		return "Cluster Scoped"
+	}
+
+	return namespace
+}
+
+func policyTitle(name string, policy *Policy) string {
+	if policy == nil || policy.Title == "" {
+		return name
+	}
+
+	return policy.Title
+}
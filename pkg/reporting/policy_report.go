@@ -9,6 +9,7 @@ import (
 
 	v1 "github.com/kyverno/policy-reporter-kyverno-plugin/pkg/crd/api/kyverno/v1"
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/kyverno"
 	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/reporting/kubernetes"
 )
 
@@ -101,10 +102,7 @@ func (g *policyReportGenerator) PerPolicyData(ctx context.Context, filter Filter
 				}
 			}
 
-			rule := result.Rule
-			if strings.HasPrefix(rule, "autogen-") {
-				rule = strings.TrimPrefix(rule, "autogen-")
-			}
+			rule := kyverno.AutogenSourceRule(result.Rule)
 
 			_, ok = val.Groups[polr.GetNamespace()].Rules[rule]
 			if !ok {
@@ -224,10 +222,7 @@ func (g *policyReportGenerator) PerNamespaceData(ctx context.Context, filter Fil
 				}
 			}
 
-			rule := result.Rule
-			if strings.HasPrefix(rule, "autogen-") {
-				rule = strings.TrimPrefix(rule, "autogen-")
-			}
+			rule := kyverno.AutogenSourceRule(result.Rule)
 
 			ruleObj, ok := val.Groups[result.Policy].Rules[rule]
 			if !ok {
@@ -11,8 +11,11 @@ import (
 )
 
 type Values struct {
-	Username string `json:"username" mapstructure:"username"`
-	Password string `json:"password" mapstructure:"password"`
+	Username    string `json:"username" mapstructure:"username"`
+	Password    string `json:"password" mapstructure:"password"`
+	Certificate string `json:"tls.crt" mapstructure:"tls.crt"`
+	Key         string `json:"tls.key" mapstructure:"tls.key"`
+	Htpasswd    string `json:"htpasswd" mapstructure:"htpasswd"`
 }
 
 type Client interface {
@@ -51,11 +54,16 @@ func (c *k8sClient) Get(ctx context.Context, name string) (Values, error) {
 		return err
 	})
 
-	values := Values{}
 	if err != nil {
-		return values, err
+		return Values{}, err
 	}
 
+	return convert(secret), nil
+}
+
+func convert(secret *corev1.Secret) Values {
+	values := Values{}
+
 	if username, ok := secret.Data["username"]; ok {
 		values.Username = string(username)
 	}
@@ -64,7 +72,19 @@ func (c *k8sClient) Get(ctx context.Context, name string) (Values, error) {
 		values.Password = string(password)
 	}
 
-	return values, nil
+	if certificate, ok := secret.Data["tls.crt"]; ok {
+		values.Certificate = string(certificate)
+	}
+
+	if key, ok := secret.Data["tls.key"]; ok {
+		values.Key = string(key)
+	}
+
+	if htpasswd, ok := secret.Data["htpasswd"]; ok {
+		values.Htpasswd = string(htpasswd)
+	}
+
+	return values
 }
 
 func NewClient(secretClient v1.SecretInterface) Client {
@@ -28,6 +28,9 @@ func newFakeClient() v1.SecretInterface {
 			"password":    []byte("password"),
 			"skipTLS":     []byte("true"),
 			"certificate": []byte("certs"),
+			"tls.crt":     []byte("cert"),
+			"tls.key":     []byte("key"),
+			"htpasswd":    []byte("reader:$2y$05$hash"),
 		},
 	}).CoreV1().Secrets("default")
 }
@@ -48,6 +51,14 @@ func Test_Client(t *testing.T) {
 		if values.Password != "password" {
 			t.Errorf("Unexpected Password: %s", values.Password)
 		}
+
+		if values.Certificate != "cert" || values.Key != "key" {
+			t.Errorf("Unexpected TLS values: %s, %s", values.Certificate, values.Key)
+		}
+
+		if values.Htpasswd != "reader:$2y$05$hash" {
+			t.Errorf("Unexpected htpasswd: %s", values.Htpasswd)
+		}
 	})
 
 	t.Run("Get values from not existing secret", func(t *testing.T) {
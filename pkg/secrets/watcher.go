@@ -0,0 +1,77 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Watcher calls its handler with the latest Values of a single Secret
+type Watcher interface {
+	// Run loads the Secret and polls it for changes until the stopper is closed
+	Run(stopper chan struct{}) error
+}
+
+// pollingWatcher only requires the get permission on the Secret, unlike an informer which needs list and watch
+type pollingWatcher struct {
+	client   Client
+	name     string
+	interval time.Duration
+	handler  func(Values)
+	lock     *sync.Mutex
+	current  Values
+}
+
+func (w *pollingWatcher) Run(stopper chan struct{}) error {
+	if w.interval <= 0 {
+		return fmt.Errorf("invalid poll interval %s for secret %s", w.interval, w.name)
+	}
+
+	ctx := wait.ContextForChannel(stopper)
+
+	// a missing Secret is picked up as soon as it is created
+	w.poll(ctx)
+
+	go wait.Until(func() { w.poll(ctx) }, w.interval, stopper)
+
+	return nil
+}
+
+func (w *pollingWatcher) poll(ctx context.Context) {
+	values, err := w.client.Get(ctx, w.name)
+	if err != nil {
+		if ctx.Err() == nil {
+			zap.L().Warn("failed to poll watched secret, keep using the last values", zap.String("secret", w.name), zap.Error(err))
+		}
+		return
+	}
+
+	w.update(values)
+}
+
+func (w *pollingWatcher) update(values Values) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if values == w.current {
+		return
+	}
+
+	w.current = values
+	w.handler(values)
+}
+
+// NewWatcher creates a Watcher which polls the Secret with the given name once per interval
+func NewWatcher(client Client, name string, interval time.Duration, handler func(Values)) Watcher {
+	return &pollingWatcher{
+		client:   client,
+		name:     name,
+		interval: interval,
+		handler:  handler,
+		lock:     &sync.Mutex{},
+	}
+}
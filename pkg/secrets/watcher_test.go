@@ -0,0 +1,107 @@
+package secrets_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/secrets"
+)
+
+func Test_Watcher(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "default"},
+		Data: map[string][]byte{
+			"username": []byte("username"),
+			"password": []byte("password"),
+		},
+	}
+
+	client := fake.NewSimpleClientset(secret)
+	values := make(chan secrets.Values, 10)
+
+	watcher := secrets.NewWatcher(secrets.NewClient(client.CoreV1().Secrets("default")), secretName, 10*time.Millisecond, func(v secrets.Values) {
+		values <- v
+	})
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	if err := watcher.Run(stop); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	next := func() secrets.Values {
+		select {
+		case v := <-values:
+			return v
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected secret values")
+		}
+
+		return secrets.Values{}
+	}
+
+	if v := next(); v.Password != "password" {
+		t.Errorf("Unexpected initial password: %s", v.Password)
+	}
+
+	secret = secret.DeepCopy()
+	secret.Data["password"] = []byte("rotated")
+
+	if _, err := client.CoreV1().Secrets("default").Update(context.Background(), secret, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := next(); v.Password != "rotated" {
+		t.Errorf("Expected rotated password, got: %s", v.Password)
+	}
+
+	for _, action := range client.Actions() {
+		if action.GetVerb() != "get" && action.GetVerb() != "update" {
+			t.Errorf("Expected only get requests for the secret, got %s", action.GetVerb())
+		}
+	}
+
+	select {
+	case v := <-values:
+		t.Errorf("Expected no handler call without changes, got %+v", v)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func Test_WatcherMissingSecret(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	values := make(chan secrets.Values, 10)
+
+	watcher := secrets.NewWatcher(secrets.NewClient(client.CoreV1().Secrets("default")), secretName, 10*time.Millisecond, func(v secrets.Values) {
+		values <- v
+	})
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	if err := watcher.Run(stop); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if _, err := client.CoreV1().Secrets("default").Create(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("created")},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case v := <-values:
+		if v.Password != "created" {
+			t.Errorf("Unexpected password: %s", v.Password)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected values of the created secret")
+	}
+}
@@ -0,0 +1,173 @@
+package stream
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a published item with its sequential ID within the epoch of the broker
+type Message[T any] struct {
+	Epoch string
+	ID    uint64
+	Data  T
+}
+
+// EventID identifies the message across restarts as "<epoch>-<id>"
+func (m Message[T]) EventID() string {
+	return formatEventID(m.Epoch, m.ID)
+}
+
+// Subscription of a single subscriber
+type Subscription[T any] struct {
+	// Messages receives all items published after subscribing
+	Messages <-chan Message[T]
+	// Backlog contains all kept items published after the last received item
+	Backlog []Message[T]
+	// Reset is set if items after the last received item are lost, because its ID
+	// belongs to another epoch or is older than the oldest kept item
+	Reset bool
+	// EventID is the ID of the last published item when subscribing
+	EventID string
+	// Cancel the subscription
+	Cancel func()
+}
+
+// Broker fans out published items to all subscribers and keeps a bounded
+// history to let reconnecting subscribers resume from their last received ID.
+// IDs are prefixed with the start time of the broker, so IDs of a previous process are detected.
+type Broker[T any] struct {
+	lock        *sync.Mutex
+	epoch       string
+	lastID      uint64
+	history     []Message[T]
+	historySize int
+	bufferSize  int
+	subscribers map[chan Message[T]]struct{}
+}
+
+// Epoch of the broker, used as prefix of all event IDs
+func (b *Broker[T]) Epoch() string {
+	return b.epoch
+}
+
+// Publish an item to all subscribers
+func (b *Broker[T]) Publish(data T) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.lastID++
+	message := Message[T]{Epoch: b.epoch, ID: b.lastID, Data: data}
+
+	if b.historySize > 0 {
+		if len(b.history) >= b.historySize {
+			b.history = b.history[1:]
+		}
+		b.history = append(b.history, message)
+	}
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- message:
+		default:
+			// slow subscribers are dropped and can resume with their last received ID
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Subscribe to new items, resuming after the last received event ID.
+// An empty lastEventID skips the history, an invalid one returns an error.
+func (b *Broker[T]) Subscribe(lastEventID string) (*Subscription[T], error) {
+	var (
+		epoch  string
+		lastID uint64
+	)
+
+	if lastEventID != "" {
+		var err error
+		if epoch, lastID, err = parseEventID(lastEventID); err != nil {
+			return nil, err
+		}
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	subscription := &Subscription[T]{Backlog: make([]Message[T], 0), EventID: formatEventID(b.epoch, b.lastID)}
+
+	if lastEventID != "" {
+		subscription.Reset = epoch != b.epoch || b.lost(lastID)
+
+		if !subscription.Reset {
+			for _, message := range b.history {
+				if message.ID > lastID {
+					subscription.Backlog = append(subscription.Backlog, message)
+				}
+			}
+		}
+	}
+
+	subscriber := make(chan Message[T], b.bufferSize)
+	b.subscribers[subscriber] = struct{}{}
+
+	subscription.Messages = subscriber
+	subscription.Cancel = func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+
+		if _, ok := b.subscribers[subscriber]; ok {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+
+	return subscription, nil
+}
+
+// lost reports whether items published after lastID are no longer kept
+func (b *Broker[T]) lost(lastID uint64) bool {
+	if lastID > b.lastID {
+		return true
+	}
+
+	if lastID == b.lastID {
+		return false
+	}
+
+	return len(b.history) == 0 || b.history[0].ID > lastID+1
+}
+
+func formatEventID(epoch string, id uint64) string {
+	return fmt.Sprintf("%s-%d", epoch, id)
+}
+
+func parseEventID(eventID string) (string, uint64, error) {
+	epoch, id, found := strings.Cut(eventID, "-")
+	if !found {
+		// IDs without epoch are from an older release
+		epoch, id = "", eventID
+	}
+
+	value, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid event id %q: %w", eventID, err)
+	}
+
+	return epoch, value, nil
+}
+
+// NewBroker constructor for Broker, the epoch is the current time
+func NewBroker[T any](historySize int) *Broker[T] {
+	return &Broker[T]{
+		lock:        &sync.Mutex{},
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 10),
+		history:     make([]Message[T], 0, historySize),
+		historySize: historySize,
+		bufferSize:  100,
+		subscribers: make(map[chan Message[T]]struct{}),
+	}
+}
@@ -0,0 +1,107 @@
+package stream_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/stream"
+)
+
+func Test_Broker(t *testing.T) {
+	broker := stream.NewBroker[string](2)
+
+	t.Run("Publish", func(t *testing.T) {
+		subscription, err := broker.Subscribe("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer subscription.Cancel()
+
+		if len(subscription.Backlog) != 0 || subscription.Reset {
+			t.Errorf("Expected empty backlog without reset, got %+v", subscription)
+		}
+
+		broker.Publish("first")
+
+		message := <-subscription.Messages
+		if message.ID != 1 || message.Data != "first" {
+			t.Errorf("Unexpected message: %+v", message)
+		}
+		if message.EventID() != broker.Epoch()+"-1" {
+			t.Errorf("Expected event ID with epoch prefix, got %s", message.EventID())
+		}
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		broker.Publish("second")
+		broker.Publish("third")
+
+		subscription, err := broker.Subscribe(broker.Epoch() + "-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer subscription.Cancel()
+
+		if subscription.Reset {
+			t.Error("Expected resume without reset")
+		}
+		if len(subscription.Backlog) != 2 {
+			t.Fatalf("Expected 2 messages in backlog, got %d", len(subscription.Backlog))
+		}
+
+		if subscription.Backlog[0].Data != "second" || subscription.Backlog[1].Data != "third" {
+			t.Errorf("Unexpected backlog: %+v", subscription.Backlog)
+		}
+	})
+
+	t.Run("Gap", func(t *testing.T) {
+		broker.Publish("fourth")
+
+		subscription, err := broker.Subscribe(broker.Epoch() + "-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer subscription.Cancel()
+
+		if !subscription.Reset {
+			t.Error("Expected reset, the second message is no longer kept")
+		}
+		if len(subscription.Backlog) != 0 {
+			t.Errorf("Expected empty backlog after reset, got %+v", subscription.Backlog)
+		}
+		if subscription.EventID != broker.Epoch()+"-4" {
+			t.Errorf("Expected the last event ID, got %s", subscription.EventID)
+		}
+	})
+
+	t.Run("Other Epoch", func(t *testing.T) {
+		for _, id := range []string{"1-3", "3"} {
+			subscription, err := broker.Subscribe(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			subscription.Cancel()
+
+			if !subscription.Reset || len(subscription.Backlog) != 0 {
+				t.Errorf("Expected reset for event ID %s, got %+v", id, subscription)
+			}
+		}
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		if _, err := broker.Subscribe(broker.Epoch() + "-abc"); err == nil {
+			t.Error("Expected error for invalid event ID")
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		subscription, err := broker.Subscribe("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		subscription.Cancel()
+
+		if _, ok := <-subscription.Messages; ok {
+			t.Error("Expected closed channel after cancel")
+		}
+	})
+}
@@ -3,30 +3,30 @@ package violation
 import "time"
 
 type Resource struct {
-	Kind      string
-	Name      string
-	Namespace string
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type Event struct {
-	Name string
-	UID  string
+	Name string `json:"name"`
+	UID  string `json:"uid"`
 }
 
 type Policy struct {
-	Name     string
-	Rule     string
-	Message  string
-	Category string
-	Severity string
+	Name     string `json:"name"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Category string `json:"category,omitempty"`
+	Severity string `json:"severity,omitempty"`
 }
 
 type PolicyViolation struct {
-	Resource  Resource
-	Policy    Policy
-	Event     Event
-	Timestamp time.Time
-	Updated   bool
+	Resource  Resource  `json:"resource"`
+	Policy    Policy    `json:"policy"`
+	Event     Event     `json:"event"`
+	Timestamp time.Time `json:"timestamp"`
+	Updated   bool      `json:"updated"`
 }
 
 // EventClient to watch for PolicyViolations in the cluster
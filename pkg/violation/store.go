@@ -0,0 +1,127 @@
+package violation
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Filter restricts the PolicyViolations returned by the Store
+type Filter struct {
+	Namespaces []string
+	Policies   []string
+	Rules      []string
+	Kinds      []string
+	Names      []string
+	Since      time.Time
+	Until      time.Time
+}
+
+// Matches checks if the PolicyViolation fulfills all configured criteria
+func (f Filter) Matches(pv PolicyViolation) bool {
+	if !contains(pv.Resource.Namespace, f.Namespaces) || !contains(pv.Resource.Kind, f.Kinds) || !contains(pv.Resource.Name, f.Names) {
+		return false
+	}
+	if !contains(pv.Policy.Name, f.Policies) || !contains(pv.Policy.Rule, f.Rules) {
+		return false
+	}
+	if !f.Since.IsZero() && pv.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && pv.Timestamp.After(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// Counts aggregates a list of PolicyViolations
+type Counts struct {
+	Total      int            `json:"total"`
+	Namespaces map[string]int `json:"namespaces"`
+	Policies   map[string]int `json:"policies"`
+	Rules      map[string]int `json:"rules"`
+	Kinds      map[string]int `json:"kinds"`
+}
+
+// Aggregate counts the PolicyViolations per namespace, policy, rule and resource kind.
+// Rules are identified by "policy/rule".
+func Aggregate(list []PolicyViolation) Counts {
+	counts := Counts{
+		Total:      len(list),
+		Namespaces: make(map[string]int),
+		Policies:   make(map[string]int),
+		Rules:      make(map[string]int),
+		Kinds:      make(map[string]int),
+	}
+
+	for _, pv := range list {
+		counts.Namespaces[pv.Resource.Namespace]++
+		counts.Policies[pv.Policy.Name]++
+		counts.Rules[pv.Policy.Name+"/"+pv.Policy.Rule]++
+		counts.Kinds[pv.Resource.Kind]++
+	}
+
+	return counts
+}
+
+// Store keeps the latest PolicyViolations in memory, bounded by its size
+type Store struct {
+	items []PolicyViolation
+	size  int
+	rwm   *sync.RWMutex
+}
+
+// Add a PolicyViolation, the oldest one is dropped if the Store is full
+func (s *Store) Add(pv PolicyViolation) {
+	if s.size <= 0 {
+		return
+	}
+
+	s.rwm.Lock()
+	defer s.rwm.Unlock()
+
+	if len(s.items) >= s.size {
+		s.items = s.items[1:]
+	}
+
+	s.items = append(s.items, pv)
+}
+
+// List all matching PolicyViolations, newest first
+func (s *Store) List(filter Filter) []PolicyViolation {
+	s.rwm.RLock()
+	defer s.rwm.RUnlock()
+
+	list := make([]PolicyViolation, 0)
+	for i := len(s.items) - 1; i >= 0; i-- {
+		if filter.Matches(s.items[i]) {
+			list = append(list, s.items[i])
+		}
+	}
+
+	return list
+}
+
+// NewStore returns a pointer to a new in memory store
+func NewStore(size int) *Store {
+	return &Store{
+		items: make([]PolicyViolation, 0, size),
+		size:  size,
+		rwm:   new(sync.RWMutex),
+	}
+}
+
+func contains(value string, values []string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
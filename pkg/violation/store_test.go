@@ -0,0 +1,48 @@
+package violation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/violation"
+)
+
+func Test_Store(t *testing.T) {
+	now := time.Now()
+
+	store := violation.NewStore(3)
+	store.Add(violation.PolicyViolation{Resource: violation.Resource{Kind: "Pod", Name: "nginx", Namespace: "default"}, Policy: violation.Policy{Name: "require-labels", Rule: "check-labels"}, Timestamp: now.Add(-3 * time.Hour)})
+	store.Add(violation.PolicyViolation{Resource: violation.Resource{Kind: "Pod", Name: "nginx", Namespace: "test"}, Policy: violation.Policy{Name: "require-labels", Rule: "check-labels"}, Timestamp: now.Add(-2 * time.Hour)})
+	store.Add(violation.PolicyViolation{Resource: violation.Resource{Kind: "Deployment", Name: "api", Namespace: "test"}, Policy: violation.Policy{Name: "require-labels", Rule: "autogen-check-labels"}, Timestamp: now.Add(-1 * time.Hour)})
+	store.Add(violation.PolicyViolation{Resource: violation.Resource{Kind: "Pod", Name: "redis", Namespace: "team-a"}, Policy: violation.Policy{Name: "disallow-latest", Rule: "check-tag"}, Timestamp: now})
+
+	t.Run("Bounded", func(t *testing.T) {
+		list := store.List(violation.Filter{})
+		if len(list) != 3 {
+			t.Fatalf("Expected 3 violations, got %d", len(list))
+		}
+
+		if list[0].Resource.Name != "redis" {
+			t.Errorf("Expected newest violation first, got %s", list[0].Resource.Name)
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		list := store.List(violation.Filter{Namespaces: []string{"test"}, Kinds: []string{"Deployment"}})
+		if len(list) != 1 || list[0].Resource.Name != "api" {
+			t.Errorf("Unexpected filter result: %+v", list)
+		}
+
+		list = store.List(violation.Filter{Since: now.Add(-90 * time.Minute), Until: now.Add(-30 * time.Minute)})
+		if len(list) != 1 || list[0].Resource.Name != "api" {
+			t.Errorf("Unexpected time range result: %+v", list)
+		}
+	})
+
+	t.Run("Aggregate", func(t *testing.T) {
+		counts := violation.Aggregate(store.List(violation.Filter{}))
+		if counts.Total != 3 || counts.Namespaces["test"] != 2 || counts.Policies["require-labels"] != 2 || counts.Rules["disallow-latest/check-tag"] != 1 || counts.Kinds["Pod"] != 2 {
+			t.Errorf("Unexpected counts: %+v", counts)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+// Package wildcard matches values against patterns with the wildcards supported by Kyverno
+package wildcard
+
+// Match reports whether the value matches the pattern. '*' matches any sequence of characters,
+// including '/' and the empty sequence, '?' matches exactly one character.
+func Match(pattern, value string) bool {
+	p, v := []rune(pattern), []rune(value)
+
+	// position of the last '*' in the pattern and of the value when it was reached
+	star, match := -1, 0
+	i, j := 0, 0
+
+	for j < len(v) {
+		switch {
+		case i < len(p) && (p[i] == '?' || p[i] == v[j]):
+			i++
+			j++
+		case i < len(p) && p[i] == '*':
+			star, match = i, j
+			i++
+		case star >= 0:
+			// let the last '*' consume one more character
+			match++
+			i, j = star+1, match
+		default:
+			return false
+		}
+	}
+
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+
+	return i == len(p)
+}
+
+// MatchAny reports whether the value matches at least one of the patterns and returns the first matching pattern
+func MatchAny(patterns []string, value string) (string, bool) {
+	for _, pattern := range patterns {
+		if Match(pattern, value) {
+			return pattern, true
+		}
+	}
+
+	return "", false
+}
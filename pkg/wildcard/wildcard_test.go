@@ -0,0 +1,45 @@
+package wildcard_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter-kyverno-plugin/pkg/wildcard"
+)
+
+func Test_Match(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		match   bool
+	}{
+		{"ghcr.io/org/app:1.2", "ghcr.io/org/app:1.2", true},
+		{"ghcr.io/org/*", "ghcr.io/org/app:1.2", true},
+		{"ghcr.io/*", "ghcr.io/org/team/app:1.2", true},
+		{"*", "", true},
+		{"ghcr.io/org/app:1.?", "ghcr.io/org/app:1.2", true},
+		{"ghcr.io/org/app:1.?", "ghcr.io/org/app:1.20", false},
+		{"*/app:*", "ghcr.io/org/app:1.2", true},
+		{"ghcr.io/*/app:*", "ghcr.io/org/other:1.2", false},
+		{"docker.io/*", "ghcr.io/org/app:1.2", false},
+		{"ghcr.io/org/app", "ghcr.io/org/app:1.2", false},
+		{"*a*b*c", "xaybzc", true},
+		{"*a*b*c", "xaybzcd", false},
+	}
+
+	for _, test := range tests {
+		if result := wildcard.Match(test.pattern, test.value); result != test.match {
+			t.Errorf("Match(%q, %q): got %v want %v", test.pattern, test.value, result, test.match)
+		}
+	}
+}
+
+func Test_MatchAny(t *testing.T) {
+	pattern, ok := wildcard.MatchAny([]string{"docker.io/*", "ghcr.io/*"}, "ghcr.io/org/app:1.2")
+	if !ok || pattern != "ghcr.io/*" {
+		t.Errorf("Expected match of ghcr.io/*, got %q", pattern)
+	}
+
+	if _, ok := wildcard.MatchAny(nil, "ghcr.io/org/app:1.2"); ok {
+		t.Error("Expected no match without patterns")
+	}
+}
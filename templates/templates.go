@@ -0,0 +1,9 @@
+// Package templates embeds the default HTML report templates
+package templates
+
+import "embed"
+
+// FS contains the default templates of the reporting directory
+//
+//go:embed reporting
+var FS embed.FS